@@ -34,6 +34,9 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err = cfg.RegisterDecks(); err != nil {
+		log.Fatal(err)
+	}
 	logger, err := cfg.App.InitLogger()
 	if err != nil {
 		log.Fatal(err)
@@ -51,8 +54,27 @@ func main() {
 	router := chi.NewRouter()
 	addMiddlewares(router)
 
+	idempotencyTTL, err := cfg.App.IdempotencyWindow()
+	if err != nil {
+		logger.Fatal("failed read config", zap.String("error", err.Error()))
+	}
+
+	rng, err := cfg.App.DefaultRNG()
+	if err != nil {
+		logger.Fatal("failed read config", zap.String("error", err.Error()))
+	}
+
+	undoPolicy, err := cfg.App.UndoPolicy()
+	if err != nil {
+		logger.Fatal("failed read config", zap.String("error", err.Error()))
+	}
+
 	// init handlers
-	cardGameHandler := handler.NewCardGameHandler(repo, logger)
+	cardGameHandler := handler.NewCardGameHandler(repo, handler.Options{
+		IdempotencyTTL: idempotencyTTL,
+		RNG:            rng,
+		UndoPolicy:     undoPolicy,
+	}, logger)
 
 	// mount routes to handlers
 	cardGameHandler.MountRoutes(router)
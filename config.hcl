@@ -12,4 +12,7 @@ app {
   listening = 8083
   prod = false
   disableStacktrace = true
+  idempotencyTTL = "24h"
+  rng = "math"
+  undo = "casual"
 }
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS original_codes TEXT[] NOT NULL DEFAULT '{}';
+
+-- Cards deck was created with are not stored before this migration and cannot be derived:
+-- deck could be created from any subset of standard cards and drawn cards are not recorded.
+-- Existing decks treat their remaining cards as original ones, so cards drawn before
+-- this migration are not known to deck and cannot be returned into it
+UPDATE decks
+SET original_codes = card_codes;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS piles
+(
+    deck_id    UUID                     NOT NULL REFERENCES decks (deck_id) ON DELETE CASCADE,
+    name       TEXT                     NOT NULL,
+    remaining  INTEGER                  NOT NULL,
+    card_codes TEXT[]                   NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (deck_id, name)
+);
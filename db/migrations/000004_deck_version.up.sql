@@ -0,0 +1,2 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
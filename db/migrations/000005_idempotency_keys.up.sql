@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    client_id    TEXT                     NOT NULL DEFAULT '',
+    key          TEXT                     NOT NULL,
+    request_hash TEXT                     NOT NULL,
+    status       INTEGER                  NOT NULL DEFAULT 0,
+    headers      JSONB                    NOT NULL DEFAULT '{}',
+    body         BYTEA,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (client_id, key)
+);
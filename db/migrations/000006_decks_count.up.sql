@@ -0,0 +1,2 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS decks_count INTEGER NOT NULL DEFAULT 1;
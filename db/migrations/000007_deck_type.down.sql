@@ -0,0 +1,2 @@
+ALTER TABLE decks
+    DROP COLUMN IF EXISTS deck_type;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS deck_type TEXT NOT NULL DEFAULT 'standard';
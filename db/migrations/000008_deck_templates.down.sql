@@ -0,0 +1,4 @@
+ALTER TABLE decks
+    DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS deck_templates;
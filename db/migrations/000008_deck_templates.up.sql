@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS deck_templates
+(
+    template_id UUID                     NOT NULL PRIMARY KEY,
+    name        TEXT                     NOT NULL,
+    cards       JSONB                    NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES deck_templates (template_id) ON DELETE RESTRICT;
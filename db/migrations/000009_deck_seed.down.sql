@@ -0,0 +1,3 @@
+ALTER TABLE decks
+    DROP COLUMN IF EXISTS seed,
+    DROP COLUMN IF EXISTS random_step;
@@ -0,0 +1,3 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS seed BIGINT,
+    ADD COLUMN IF NOT EXISTS random_step BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS rng TEXT NOT NULL DEFAULT 'math';
@@ -0,0 +1,5 @@
+ALTER TABLE decks
+    DROP COLUMN IF EXISTS commitment,
+    DROP COLUMN IF EXISTS client_seed,
+    DROP COLUMN IF EXISTS server_seed,
+    DROP COLUMN IF EXISTS is_closed;
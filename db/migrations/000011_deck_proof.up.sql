@@ -0,0 +1,5 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS commitment TEXT,
+    ADD COLUMN IF NOT EXISTS client_seed TEXT,
+    ADD COLUMN IF NOT EXISTS server_seed TEXT,
+    ADD COLUMN IF NOT EXISTS is_closed BOOLEAN NOT NULL DEFAULT false;
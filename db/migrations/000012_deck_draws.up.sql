@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS deck_draws
+(
+    deck_id    UUID                     NOT NULL REFERENCES decks (deck_id) ON DELETE CASCADE,
+    batch      INTEGER                  NOT NULL,
+    source     TEXT                     NOT NULL,
+    pile       TEXT,
+    card_codes TEXT[]                   NOT NULL,
+    requester  TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (deck_id, batch)
+);
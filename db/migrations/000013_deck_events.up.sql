@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS deck_events
+(
+    deck_id      UUID                     NOT NULL REFERENCES decks (deck_id) ON DELETE CASCADE,
+    event_number INTEGER                  NOT NULL,
+    event_type   TEXT                     NOT NULL,
+    data         JSONB                    NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (deck_id, event_number)
+);
+
+-- decks created before events were stored start from snapshot of their current state
+INSERT INTO deck_events (deck_id, event_number, event_type, data, created_at)
+SELECT d.deck_id,
+       d.version,
+       'snapshot',
+       jsonb_build_object(
+               'cards', jsonb_build_object('order', to_jsonb(d.card_codes)),
+               'is_shuffled', d.is_shuffled,
+               'is_closed', d.is_closed,
+               'random', jsonb_build_object('seed', d.seed, 'step', d.random_step),
+               'clear_piles', true,
+               'piles', COALESCE((SELECT jsonb_object_agg(p.name, jsonb_build_object('order', to_jsonb(p.card_codes)))
+                                  FROM piles p
+                                  WHERE p.deck_id = d.deck_id), '{}'::jsonb)
+           ),
+       d.updated_at
+FROM decks d
+ON CONFLICT DO NOTHING;
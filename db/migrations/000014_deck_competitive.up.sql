@@ -0,0 +1,2 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS is_competitive BOOLEAN NOT NULL DEFAULT false;
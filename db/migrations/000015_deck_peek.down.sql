@@ -0,0 +1,5 @@
+ALTER TABLE deck_draws
+    DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE decks
+    DROP COLUMN IF EXISTS no_peek;
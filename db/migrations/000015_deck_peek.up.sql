@@ -0,0 +1,5 @@
+ALTER TABLE decks
+    ADD COLUMN IF NOT EXISTS no_peek BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE deck_draws
+    ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'draw';
@@ -2,6 +2,9 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	apimodels "github.com/card-deck/internal/api/models"
 	"github.com/card-deck/internal/models"
@@ -17,13 +20,35 @@ import (
 // CardGameHandler represents type to handle income HTTP requests for card game
 type CardGameHandler struct {
 	repo   *repository.Repository
+	opts   Options
 	logger *zap.Logger
 }
 
+// maxDrawCount is a maximum count of cards drawn at once,
+// it is a size of the biggest possible shoe
+const maxDrawCount = deckhelper.MaxDecksCount * deckhelper.MaxDeckSize
+
+// maxShuffleTimes is a maximum count of repeats of shuffle method
+const maxShuffleTimes = 100
+
+// maxClientSeedLength is a maximum length of client seed of provably fair deck
+const maxClientSeedLength = 256
+
+// Options represents configurable behaviour of CardGameHandler
+type Options struct {
+	// IdempotencyTTL is how long responses are stored for Idempotency-Key header
+	IdempotencyTTL time.Duration
+	// RNG is a random generator of decks created without explicit one
+	RNG string
+	// UndoPolicy is which decks are allowed to undo their operations
+	UndoPolicy models.UndoPolicy
+}
+
 // NewCardGameHandler creates new instance of CardGameHandler
-func NewCardGameHandler(repo *repository.Repository, logger *zap.Logger) *CardGameHandler {
+func NewCardGameHandler(repo *repository.Repository, opts Options, logger *zap.Logger) *CardGameHandler {
 	return &CardGameHandler{
 		repo:   repo,
+		opts:   opts,
 		logger: logger,
 	}
 }
@@ -31,9 +56,29 @@ func NewCardGameHandler(repo *repository.Repository, logger *zap.Logger) *CardGa
 // MountRoutes mounts the endpoint routes to the router instance
 func (h *CardGameHandler) MountRoutes(router chi.Router) {
 	router.Group(func(r chi.Router) {
-		r.Method(http.MethodPost, "/v1/deck", httphelper.Handler(h.CreateDeck))
+		r.Method(http.MethodPost, "/v1/deck", httphelper.Handler(h.idempotent(h.CreateDeck)))
+		r.Method(http.MethodGet, "/v1/deck/types", httphelper.Handler(h.ListDeckTypes))
 		r.Method(http.MethodGet, "/v1/deck/{deckID}", httphelper.Handler(h.OpenDeck))
-		r.Method(http.MethodPatch, "/v1/deck/{deckID}/cards", httphelper.Handler(h.DrawCards))
+		r.Method(http.MethodPatch, "/v1/deck/{deckID}/cards", httphelper.Handler(h.idempotent(h.DrawCards)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/deal", httphelper.Handler(h.idempotent(h.DealCards)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/return", httphelper.Handler(h.idempotent(h.ReturnCards)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/shuffle", httphelper.Handler(h.idempotent(h.ShuffleDeck)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/sort", httphelper.Handler(h.idempotent(h.SortDeck)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/close", httphelper.Handler(h.idempotent(h.CloseDeck)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/undo", httphelper.Handler(h.idempotent(h.UndoDeck)))
+		r.Method(http.MethodGet, "/v1/deck/{deckID}/proof", httphelper.Handler(h.GetDeckProof))
+		r.Method(http.MethodGet, "/v1/deck/{deckID}/history", httphelper.Handler(h.GetDrawHistory))
+		r.Method(http.MethodGet, "/v1/deck/{deckID}/peek", httphelper.Handler(h.PeekCards))
+		r.Method(http.MethodPost, "/v1/template", httphelper.Handler(h.idempotent(h.CreateTemplate)))
+		r.Method(http.MethodGet, "/v1/template", httphelper.Handler(h.ListTemplates))
+		r.Method(http.MethodGet, "/v1/template/{templateID}", httphelper.Handler(h.GetTemplate))
+		r.Method(http.MethodPut, "/v1/template/{templateID}", httphelper.Handler(h.UpdateTemplate))
+		r.Method(http.MethodDelete, "/v1/template/{templateID}", httphelper.Handler(h.DeleteTemplate))
+		r.Method(http.MethodGet, "/v1/deck/{deckID}/pile", httphelper.Handler(h.ListPiles))
+		r.Method(http.MethodGet, "/v1/deck/{deckID}/pile/{name}", httphelper.Handler(h.OpenPile))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/pile/{name}", httphelper.Handler(h.idempotent(h.AddToPile)))
+		r.Method(http.MethodPost, "/v1/deck/{deckID}/pile/{name}/shuffle", httphelper.Handler(h.idempotent(h.ShufflePile)))
+		r.Method(http.MethodPatch, "/v1/deck/{deckID}/pile/{name}/cards", httphelper.Handler(h.idempotent(h.DrawFromPile)))
 	})
 }
 
@@ -45,31 +90,117 @@ func (h *CardGameHandler) CreateDeck(w http.ResponseWriter, r *http.Request) err
 	if err := httphelper.ReadJSON(r, &payload); err != nil {
 		return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
 	}
-	if !deckhelper.IsValidCodes(payload.Cards) {
-		return errors.New(errors.InvalidInput, "given cards is not valid")
+	if payload.Jokers != nil && *payload.Jokers > deckhelper.MaxJokers {
+		return errors.Newf(errors.InvalidInput, "jokers cannot be more than %d", deckhelper.MaxJokers)
+	}
+	if payload.DecksCount > deckhelper.MaxDecksCount {
+		return errors.Newf(errors.InvalidInput, "decks_count cannot be more than %d", deckhelper.MaxDecksCount)
+	}
+	if payload.DecksCount == 0 {
+		payload.DecksCount = 1
 	}
 
 	deck := &models.Deck{
-		IsShuffled: payload.IsShuffled,
-		CardCodes:  payload.Cards,
+		IsShuffled:    payload.IsShuffled,
+		DecksCount:    payload.DecksCount,
+		CardCodes:     payload.Cards,
+		RNG:           payload.RNG,
+		IsCompetitive: payload.Competitive,
+		NoPeek:        payload.NoPeek,
+	}
+	if payload.ProvablyFair && payload.RNG != "" {
+		return errors.New(errors.InvalidInput, "rng cannot be used with provably_fair")
+	}
+	if deck.RNG == "" {
+		deck.RNG = h.opts.RNG
+	}
+	if deck.RNG == "" {
+		deck.RNG = deckhelper.RNGMath
+	}
+	if !deckhelper.IsValidRNG(deck.RNG) {
+		return errors.Newf(errors.InvalidInput, "unknown rng %q", deck.RNG)
+	}
+	if err := reseedDeck(deck, payload.Seed); err != nil {
+		return err
+	}
+	if payload.ProvablyFair && payload.Seed != nil {
+		return errors.New(errors.InvalidInput, "seed cannot be used with provably_fair")
+	}
+	if len(payload.ClientSeed) > maxClientSeedLength {
+		return errors.Newf(errors.InvalidInput, "client_seed cannot be longer than %d", maxClientSeedLength)
+	}
+
+	var def *deckhelper.DeckDefinition
+	if payload.TemplateID != "" {
+		if payload.Type != "" {
+			return errors.New(errors.InvalidInput, "type and template_id cannot be used together")
+		}
+		template, err := h.repo.GetTemplateByID(payload.TemplateID)
+		if err != nil {
+			return repoError(err, "failed get template")
+		}
+		if def, err = template.Definition(); err != nil {
+			return errors.Wrap(err, errors.Internal, "template is not valid")
+		}
+		deck.Type = models.TemplateDeckType
+		deck.TemplateID = &template.TemplateID
+	} else {
+		var ok bool
+		if def, ok = deckhelper.Lookup(payload.Type); !ok {
+			return errors.Newf(errors.InvalidInput, "unknown deck type %q", payload.Type)
+		}
+		deck.Type = def.Name
+	}
+
+	// deck definition may declare its own jokers count, explicit count takes precedence
+	jokers := def.Jokers
+	if payload.Jokers != nil {
+		jokers = *payload.Jokers
 	}
 
 	if len(deck.CardCodes) == 0 {
-		deck.CardCodes = deckhelper.CreateDefaultCodes()
+		deck.CardCodes = def.ShoeCodes(deck.DecksCount, jokers)
+	} else {
+		for i := uint(0); i < deck.DecksCount; i++ {
+			deck.CardCodes = append(deck.CardCodes, deckhelper.CreateJokerCodes(jokers)...)
+		}
+	}
+
+	if !def.IsValidCodes(deck.CardCodes, deck.DecksCount) {
+		return errors.New(errors.InvalidInput, "given cards is not valid")
 	}
+	deck.OriginalCodes = append([]string(nil), deck.CardCodes...)
 
-	if deck.IsShuffled {
-		deckhelper.ShuffleDeck(deck.CardCodes)
+	if payload.ProvablyFair {
+		serverSeed, err := deckhelper.NewServerSeed()
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed generate server seed")
+		}
+		deck.ShuffleFair(serverSeed, payload.ClientSeed)
+	} else if deck.IsShuffled {
+		deckhelper.Shuffle(deck.CardCodes, deck.Random())
 	}
 
-	if err := h.repo.CreateDeck(deck); err != nil {
-		return errors.Wrap(err, errors.Internal, "failed store new deck")
+	if err := h.repo.WithContext(r.Context()).CreateDeck(deck); err != nil {
+		return repoError(err, "failed store new deck")
 	}
+	w.Header().Set("ETag", httphelper.ETag(deck.Version))
 
 	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
 }
 
-// OpenDeck returns all cards into deck by it's ID
+// ListDeckTypes returns names of all known deck types
+// Route /v1/deck/types [get]
+func (h *CardGameHandler) ListDeckTypes(w http.ResponseWriter, _ *http.Request) error {
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deckhelper.DefinitionNames())
+}
+
+// OpenDeck returns all cards into deck by it's ID,
+// cards are shown sorted if sort query parameter is given, stored order is not changed.
+// Cards out of deck (drawn or held in piles) are shown if show_drawn query parameter is set.
+// State of deck after given event is replayed from deck events if at_event query parameter is set.
+// Remaining cards are not shown for decks created with no_peek
+// and for provably fair decks till their order is revealed
 // Route /v1/deck/{deckID} [get]
 func (h *CardGameHandler) OpenDeck(w http.ResponseWriter, r *http.Request) error {
 	deckID := chi.URLParam(r, "deckID")
@@ -82,16 +213,57 @@ func (h *CardGameHandler) OpenDeck(w http.ResponseWriter, r *http.Request) error
 		return errors.New(errors.NotFound, "deck not found")
 	}
 
-	cards, err := models.BuildCardsFromCodes(deck.CardCodes)
+	// replayed state is hidden as long as current deck is hidden
+	hidesOrder := deck.HidesOrder()
+	if atEvent := r.URL.Query().Get("at_event"); atEvent != "" {
+		if deck, err = h.replayDeck(deck, atEvent); err != nil {
+			return err
+		}
+	}
+
+	etag := httphelper.ETag(deck.Version)
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && httphelper.MatchETag(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	def, err := h.deckDefinition(deck)
 	if err != nil {
-		return errors.New(errors.Internal, "failed map codes to cards")
+		return err
+	}
+
+	if !hidesOrder {
+		codes := []string(deck.CardCodes)
+		if query := r.URL.Query(); query.Get("sort") != "" {
+			payload := apimodels.SortCardsRequest{
+				Sort:  models.SortOrder(query.Get("sort")),
+				Trump: query.Get("trump"),
+			}
+			if suits := query.Get("suits"); suits != "" {
+				payload.Suits = strings.Split(suits, ",")
+			}
+			if codes, err = sortCodes(&payload, def, codes); err != nil {
+				return err
+			}
+		}
+
+		if deck.Cards, err = buildCards(def, codes); err != nil {
+			return err
+		}
+	}
+
+	if showDrawn, _ := strconv.ParseBool(r.URL.Query().Get("show_drawn")); showDrawn {
+		if deck.Drawn, err = buildCards(def, models.SubtractCodes(deck.OriginalCodes, deck.CardCodes)); err != nil {
+			return err
+		}
 	}
-	deck.Cards = cards
 
 	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
 }
 
-// DrawCards draws [N] cards from deck by it's ID
+// DrawCards draws [N] cards from deck by it's ID,
+// cards are taken from the top of the deck unless other mode is given
 // Route /v1/deck/{deckID}/cards [patch]
 func (h *CardGameHandler) DrawCards(w http.ResponseWriter, r *http.Request) error {
 	var payload apimodels.DrawCardsRequest
@@ -105,39 +277,463 @@ func (h *CardGameHandler) DrawCards(w http.ResponseWriter, r *http.Request) erro
 		return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
 	}
 
-	if payload.Count > 52 || payload.Count == 0 {
-		return errors.New(errors.InvalidInput, "count cannot be more than 52 or 0")
+	if err := validateDrawRequest(&payload); err != nil {
+		return err
+	}
+
+	var cards models.Cards
+	event := models.NewDeckEvent(models.EventDrawn)
+	_, err := h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		if err := checkFairDraw(deck, payload.Mode); err != nil {
+			return err
+		}
+		def, err := h.deckDefinition(deck)
+		if err != nil {
+			return err
+		}
+
+		if err = reseedDeck(deck, payload.Seed); err != nil {
+			return err
+		}
+		drawnCodes, leftCodes, err := drawCodes(&payload, def, "deck", deck.CardCodes, deck.Random)
+		if err != nil {
+			return err
+		}
+		event.Data.Codes = drawnCodes
+
+		cards, err = buildCards(def, drawnCodes)
+		if err != nil {
+			return err
+		}
+
+		deck.CardCodes = leftCodes
+
+		return addDraw(tx, &models.Draw{
+			DeckID:    deck.DeckID,
+			Source:    models.DrawSourceDeck,
+			CardCodes: drawnCodes,
+		})
+	})
+	if err != nil {
+		return repoError(err, "failed draw cards")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, cards)
+}
+
+// ReturnCards puts drawn cards back into deck by it's ID
+// Route /v1/deck/{deckID}/return [post]
+func (h *CardGameHandler) ReturnCards(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.ReturnCardsRequest
+
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	if err := httphelper.ReadJSON(r, &payload); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+	}
+
+	if !payload.Mode.IsValid() {
+		return errors.Newf(errors.InvalidInput, "unknown return mode %q", payload.Mode)
+	}
+	if len(payload.Codes) == 0 {
+		return errors.New(errors.InvalidInput, "codes is required")
+	}
+
+	event := models.NewDeckEvent(models.EventReturned)
+	event.Data.Codes = payload.Codes
+	deck, err := h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		if deck.IsProvablyFair() {
+			return errors.New(errors.Conflict, "cards cannot be returned into provably fair deck")
+		}
+		piles, err := tx.GetPilesByDeckID(deckID)
+		if err != nil {
+			return errors.New(errors.Internal, "failed get piles")
+		}
+
+		if foreign := models.MissingCodes(payload.Codes, deck.OriginalCodes); len(foreign) > 0 {
+			return errors.Newf(errors.InvalidInput, "cards never belonged to deck: %s", strings.Join(foreign, ", "))
+		}
+		if notDrawn := models.ExcessCodes(payload.Codes, drawnLooseCodes(deck, piles)); len(notDrawn) > 0 {
+			return errors.Newf(errors.Conflict, "cards are not drawn from deck or held in pile: %s", strings.Join(notDrawn, ", "))
+		}
+
+		var rnd deckhelper.Random
+		if payload.Mode == models.ReturnModeRandom {
+			rnd = deck.Random()
+		}
+		codes, err := models.ReturnCodes(payload.Mode, payload.Codes, deck.CardCodes, rnd)
+		if err != nil {
+			return errors.New(errors.Internal, "failed return cards into deck")
+		}
+		deck.CardCodes = codes
+
+		return nil
+	})
+	if err != nil {
+		return repoError(err, "failed return cards")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
+}
+
+// ShuffleDeck reshuffles remaining cards into deck by it's ID,
+// drawn cards (including piles) are returned into deck before shuffle if include_drawn is set.
+// Shuffle method (uniform by default) is repeated given times
+// Route /v1/deck/{deckID}/shuffle [post]
+func (h *CardGameHandler) ShuffleDeck(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.ShuffleDeckRequest
+
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	if r.ContentLength != 0 {
+		if err := httphelper.ReadJSON(r, &payload); err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+		}
+	}
+
+	shuffler, ok := deckhelper.LookupShuffler(payload.Method)
+	if !ok {
+		return errors.Newf(errors.InvalidInput, "unknown shuffle method %q, use one of: %s",
+			payload.Method, strings.Join(deckhelper.ShufflerNames(), ", "))
+	}
+	if payload.Times > maxShuffleTimes {
+		return errors.Newf(errors.InvalidInput, "times cannot be more than %d", maxShuffleTimes)
+	}
+	if payload.Times == 0 {
+		payload.Times = 1
+	}
+
+	deck, err := h.modifyDeck(w, r, deckID, models.NewDeckEvent(models.EventShuffled), func(tx *repository.Repository, deck *models.Deck) error {
+		if deck.IsProvablyFair() {
+			return errors.New(errors.Conflict, "provably fair deck cannot be reshuffled")
+		}
+		if payload.IncludeDrawn {
+			if err := tx.DeletePilesByDeckID(deck.DeckID); err != nil {
+				return errors.New(errors.Internal, "failed recall cards from piles")
+			}
+			deck.CardCodes = append([]string(nil), deck.OriginalCodes...)
+		}
+		if err := reseedDeck(deck, payload.Seed); err != nil {
+			return err
+		}
+		deckhelper.Repeat(shuffler, payload.Times).Shuffle(deck.CardCodes, deck.Random())
+		deck.IsShuffled = true
+
+		return nil
+	})
+	if err != nil {
+		return repoError(err, "failed shuffle deck")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
+}
+
+// SortDeck sorts remaining cards into deck by it's ID from the lowest to the highest,
+// sorted cards are not shown for decks which hide their order
+// Route /v1/deck/{deckID}/sort [post]
+func (h *CardGameHandler) SortDeck(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.SortCardsRequest
+
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	if r.ContentLength != 0 {
+		if err := httphelper.ReadJSON(r, &payload); err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+		}
+	}
+
+	var def *deckhelper.DeckDefinition
+	deck, err := h.modifyDeck(w, r, deckID, models.NewDeckEvent(models.EventSorted), func(_ *repository.Repository, deck *models.Deck) error {
+		if deck.IsProvablyFair() {
+			return errors.New(errors.Conflict, "provably fair deck cannot be sorted")
+		}
+		var err error
+		if def, err = h.deckDefinition(deck); err != nil {
+			return err
+		}
+		codes, err := sortCodes(&payload, def, deck.CardCodes)
+		if err != nil {
+			return err
+		}
+		deck.CardCodes = codes
+		deck.IsShuffled = false
+
+		return nil
+	})
+	if err != nil {
+		return repoError(err, "failed sort deck")
+	}
+
+	if !deck.HidesOrder() {
+		if deck.Cards, err = buildCards(def, deck.CardCodes); err != nil {
+			return err
+		}
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
+}
+
+// CloseDeck closes deck by it's ID, closed deck cannot be modified
+// and reveals its proof if deck is provably fair
+// Route /v1/deck/{deckID}/close [post]
+func (h *CardGameHandler) CloseDeck(w http.ResponseWriter, r *http.Request) error {
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	deck, err := h.modifyDeck(w, r, deckID, models.NewDeckEvent(models.EventClosed), func(_ *repository.Repository, deck *models.Deck) error {
+		deck.IsClosed = true
+		return nil
+	})
+	if err != nil {
+		return repoError(err, "failed close deck")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
+}
+
+// replayDeck returns state of deck after event of given number
+func (h *CardGameHandler) replayDeck(deck *models.Deck, atEvent string) (*models.Deck, error) {
+	number, err := strconv.ParseUint(atEvent, 10, 32)
+	if err != nil || number == 0 {
+		return nil, errors.New(errors.InvalidInput, "at_event should be positive number")
+	}
+	if uint(number) > deck.Version {
+		return nil, errors.Newf(errors.NotFound, "event %d not found", number)
+	}
+
+	events, err := h.repo.GetDeckEvents(deck.DeckID, uint(number))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "failed get deck events")
+	}
+	replayed, _, err := models.ReplayDeck(deck, events, uint(number))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.NotFound, err.Error())
+	}
+
+	return replayed, nil
+}
+
+// GetDeckProof returns commitment of provably fair deck by it's ID,
+// server seed and order are revealed once deck is finished or closed
+// Route /v1/deck/{deckID}/proof [get]
+func (h *CardGameHandler) GetDeckProof(w http.ResponseWriter, r *http.Request) error {
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
 	}
 
 	deck, err := h.repo.GetDeckByID(deckID)
 	if err != nil {
-		return errors.New(errors.NotFound, "deck not found")
+		return repoError(err, "failed get deck")
+	}
+
+	proof, err := deck.Proof()
+	if err != nil {
+		return errors.Wrap(err, errors.NotFound, err.Error())
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, proof)
+}
+
+// sortCodes sorts codes of deck definition according to sort request,
+// suits are listed from the lowest to the highest, "bridge" can be used instead of the list
+func sortCodes(payload *apimodels.SortCardsRequest, def *deckhelper.DeckDefinition, codes []string) ([]string, error) {
+	if !payload.Sort.IsValid() {
+		return nil, errors.Newf(errors.InvalidInput, "unknown sort order %q", payload.Sort)
 	}
 
-	if deck.Remaining == 0 {
-		return errors.New(errors.InvalidInput, "deck remaining 0 cards")
+	ordering := def.Ordering(payload.Sort != models.SortOrderAceLow)
+	if len(payload.Suits) == 1 && payload.Suits[0] == "bridge" {
+		ordering.Suits = deckhelper.BridgeSuits
+	} else {
+		for _, suit := range payload.Suits {
+			ordering.Suits = append(ordering.Suits, deckhelper.Suit(suit))
+		}
 	}
-	if deck.Remaining < payload.Count {
-		payload.Count = deck.Remaining
+	ordering.Trump = deckhelper.Suit(payload.Trump)
+	if err := ordering.Validate(def); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, err.Error())
 	}
 
-	drawnCodes, err := models.DrawRandomNCars(payload.Count, deck.CardCodes)
+	sorted, err := ordering.SortCodes(def, codes)
 	if err != nil {
-		return errors.New(errors.Internal, "failed draw cards from deck")
+		return nil, errors.Wrap(err, errors.Internal, "failed sort cards")
 	}
 
-	cards, err := models.BuildCardsFromCodes(drawnCodes)
+	return sorted, nil
+}
+
+// reseedDeck starts new random sequence of deck if seed is given,
+// decks using crypto random generator cannot be seeded
+func reseedDeck(deck *models.Deck, seed *int64) error {
+	if seed == nil {
+		return nil
+	}
+	if deck.RNG == deckhelper.RNGCrypto {
+		return errors.Newf(errors.InvalidInput, "seed cannot be used with %q rng", deckhelper.RNGCrypto)
+	}
+	deck.Reseed(*seed)
+	return nil
+}
+
+// validateDrawRequest validates draw request and sets count of cards
+// for modes which don't use it directly
+func validateDrawRequest(payload *apimodels.DrawCardsRequest) error {
+	if !payload.Mode.IsValid() {
+		return errors.Newf(errors.InvalidInput, "unknown draw mode %q", payload.Mode)
+	}
+	switch payload.Mode {
+	case models.DrawModePosition:
+		payload.Count = uint(len(payload.Positions))
+	case models.DrawModeCode:
+		payload.Count = uint(len(payload.Codes))
+	}
+
+	if payload.Count > maxDrawCount || payload.Count == 0 {
+		return errors.Newf(errors.InvalidInput, "count cannot be more than %d or 0", maxDrawCount)
+	}
+
+	return nil
+}
+
+// checkFairDraw allows to draw cards of provably fair deck from the top or the bottom only,
+// so drawn cards are always defined by committed order and cannot be chosen
+func checkFairDraw(deck *models.Deck, mode models.DrawMode) error {
+	if !deck.IsProvablyFair() {
+		return nil
+	}
+	switch mode {
+	case "", models.DrawModeTop, models.DrawModeBottom:
+		return nil
+	}
+	return errors.Newf(errors.Conflict, "provably fair deck cannot be drawn in %q mode", mode)
+}
+
+// drawCodes draws codes from given source (deck or pile) according to draw request,
+// returns drawn codes and codes left in source. random is called for random mode only,
+// so seeded deck sequence is advanced by random operations only
+func drawCodes(
+	payload *apimodels.DrawCardsRequest,
+	def *deckhelper.DeckDefinition,
+	source string,
+	codes []string,
+	random func() deckhelper.Random,
+) ([]string, []string, error) {
+	if len(codes) == 0 {
+		return nil, nil, errors.Newf(errors.InvalidInput, "%s remaining 0 cards", source)
+	}
+
+	if payload.Mode == models.DrawModeCode {
+		if !def.IsValidCodes(payload.Codes, deckhelper.MaxDecksCount) {
+			return nil, nil, errors.New(errors.InvalidInput, "given codes is not valid")
+		}
+
+		drawn, left, missing := models.DrawCardsByCodes(payload.Codes, codes)
+		if len(missing) > 0 {
+			return nil, nil, errors.Newf(errors.Conflict, "cards not found in %s: %s", source, strings.Join(missing, ", "))
+		}
+		return drawn, left, nil
+	}
+
+	count := payload.Count
+	if uint(len(codes)) < count {
+		count = uint(len(codes))
+	}
+
+	var rnd deckhelper.Random
+	if payload.Mode == models.DrawModeRandom {
+		rnd = random()
+	}
+	drawn, left, err := models.DrawCodes(payload.Mode, count, payload.Positions, codes, rnd)
 	if err != nil {
-		return errors.New(errors.Internal, "failed map codes to cards")
+		if payload.Mode == models.DrawModePosition {
+			return nil, nil, errors.Wrap(err, errors.InvalidInput, err.Error())
+		}
+		return nil, nil, errors.Newf(errors.Internal, "failed draw cards from %s", source)
 	}
 
-	leftCodes := models.RemoveDrawnCodes(drawnCodes, deck.CardCodes)
+	return drawn, left, nil
+}
 
-	deck.CardCodes = leftCodes
+// deckDefinition returns definition of deck either registered or stored as template
+func (h *CardGameHandler) deckDefinition(deck *models.Deck) (*deckhelper.DeckDefinition, error) {
+	if deck.TemplateID == nil {
+		def, err := deck.Definition()
+		if err != nil {
+			return nil, errors.Wrap(err, errors.Internal, "unknown deck type")
+		}
+		return def, nil
+	}
 
-	if err = h.repo.UpdateDeck(deck); err != nil {
-		return errors.New(errors.Internal, "failed draw cards")
+	template, err := h.repo.GetTemplateByID(*deck.TemplateID)
+	if err != nil {
+		return nil, repoError(err, "failed get deck template")
+	}
+	def, err := template.Definition()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.Internal, "deck template is not valid")
 	}
 
-	return httphelper.WriteSuccessResponse(w, http.StatusOK, cards)
+	return def, nil
+}
+
+// buildCards builds card objects from codes according to deck definition
+func buildCards(def *deckhelper.DeckDefinition, codes []string) (models.Cards, error) {
+	cards, err := models.BuildDeckCards(def, codes)
+	if err != nil {
+		return nil, errors.New(errors.Internal, "failed map codes to cards")
+	}
+	return cards, nil
+}
+
+// drawnLooseCodes returns codes drawn from deck which are not held in any pile
+func drawnLooseCodes(deck *models.Deck, piles []*models.Pile) []string {
+	return models.SubtractCodes(deck.OriginalCodes, append(models.PilesCodes(piles), deck.CardCodes...))
+}
+
+// modifyDeck modifies deck by it's ID under lock, checks If-Match precondition
+// against current deck version and sets ETag of the new version, closed deck cannot be modified
+func (h *CardGameHandler) modifyDeck(
+	w http.ResponseWriter,
+	r *http.Request,
+	deckID string,
+	event *models.DeckEvent,
+	modify func(tx *repository.Repository, deck *models.Deck) error,
+) (*models.Deck, error) {
+	ifMatch := r.Header.Get("If-Match")
+	deck, err := h.repo.WithContext(r.Context()).ModifyDeck(deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		if ifMatch != "" && !httphelper.MatchStrongETag(ifMatch, httphelper.ETag(deck.Version)) {
+			return errors.New(errors.PreconditionFailed, "deck has been changed since it was read")
+		}
+		if deck.IsClosed {
+			return errors.New(errors.Conflict, "deck is closed")
+		}
+		return modify(tx, deck)
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.Header().Set("ETag", httphelper.ETag(deck.Version))
+
+	return deck, nil
+}
+
+// repoError passes application errors as is
+// and hides details of any other error behind given context
+func repoError(err error, ctx string) error {
+	if e, ok := err.(*errors.Error); ok {
+		return e
+	}
+	return errors.Wrap(err, errors.Internal, ctx)
 }
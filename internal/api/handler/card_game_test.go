@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/internal/repository"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// required for database
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+// newTestHandler connects to database given by TEST_DB_URL env, applies migrations
+// and creates handler with given options, test is skipped if env is not set
+func newTestHandler(t *testing.T, opts Options) *CardGameHandler {
+	dbURL := os.Getenv("TEST_DB_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+
+	m, err := migrate.New("file://../../../db/migrations", dbURL)
+	require.NoError(t, err)
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		require.NoError(t, err)
+	}
+
+	db, err := sqlx.Connect("postgres", dbURL)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewCardGameHandler(repository.NewRepository(db, zap.NewNop()), opts, zap.NewNop())
+}
+
+// newTestRouter mounts routes of test handler with given options
+func newTestRouter(t *testing.T, opts Options) http.Handler {
+	router := chi.NewRouter()
+	newTestHandler(t, opts).MountRoutes(router)
+	return router
+}
+
+// doRequest sends request with JSON body (if given) to router and returns recorded response
+func doRequest(t *testing.T, router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// createTestDeck creates deck by given request body and returns it
+func createTestDeck(t *testing.T, router http.Handler, body interface{}) *models.Deck {
+	w := doRequest(t, router, http.MethodPost, "/v1/deck", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var deck models.Deck
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deck))
+	return &deck
+}
+
+// decodeObject decodes JSON object of response body
+func decodeObject(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	var object map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &object))
+	return object
+}
+
+// cardCodes returns codes of cards of given response object, e.g. deck or pile
+func cardCodes(t *testing.T, w *httptest.ResponseRecorder) []string {
+	var object struct {
+		Cards models.Cards `json:"cards"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &object))
+
+	codes := make([]string, 0, len(object.Cards))
+	for _, card := range object.Cards {
+		codes = append(codes, card.Code)
+	}
+	return codes
+}
+
+func TestShuffleDeck(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	codes := []string{"AS", "2S", "3S", "4S", "5S", "6S", "7S", "8S"}
+	deck := createTestDeck(t, router, map[string]interface{}{"cards": codes})
+	require.False(t, deck.IsShuffled)
+	path := "/v1/deck/" + deck.DeckID
+
+	w := doRequest(t, router, http.MethodPatch, path+"/cards", map[string]interface{}{"count": 3})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodPost, path+"/pile/hand", map[string]interface{}{"codes": []string{"AS"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// only remaining cards are reshuffled by default
+	w = doRequest(t, router, http.MethodPost, path+"/shuffle", map[string]interface{}{"seed": 3})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var shuffled models.Deck
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &shuffled))
+	require.True(t, shuffled.IsShuffled)
+	require.Equal(t, uint(5), shuffled.Remaining)
+	require.Equal(t, uint(4), shuffled.Version)
+	require.True(t, shuffled.UpdatedAt.After(deck.UpdatedAt))
+
+	expected := append([]string(nil), codes[3:]...)
+	deckhelper.Shuffle(expected, deckhelper.NewSeededRandom(3, 0))
+	w = doRequest(t, router, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, expected, cardCodes(t, w))
+	require.Equal(t, true, decodeObject(t, w)["is_shuffled"])
+	w = doRequest(t, router, http.MethodGet, path+"/pile/hand", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// include_drawn recalls cards from piles and reshuffles all original cards
+	w = doRequest(t, router, http.MethodPost, path+"/shuffle", map[string]interface{}{"include_drawn": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, 8.0, decodeObject(t, w)["remaining"])
+	w = doRequest(t, router, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.ElementsMatch(t, codes, cardCodes(t, w))
+	w = doRequest(t, router, http.MethodGet, path+"/pile", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[]`, w.Body.String())
+}
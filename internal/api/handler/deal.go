@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	apimodels "github.com/card-deck/internal/api/models"
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/internal/repository"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+	"github.com/go-chi/chi/v5"
+)
+
+// DealCards deals [N] cards to each player from the top of deck by it's ID,
+// one card at a time round-robin. Players are given by count or by names,
+// cards of each player are put on top of the pile named after player.
+// Either all cards are dealt or none of them
+// Route /v1/deck/{deckID}/deal [post]
+func (h *CardGameHandler) DealCards(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.DealRequest
+
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	if err := httphelper.ReadJSON(r, &payload); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+	}
+
+	players := payload.Players.Count
+	if players == 0 {
+		return errors.New(errors.InvalidInput, "players is required")
+	}
+	if payload.CardsEach == 0 {
+		return errors.New(errors.InvalidInput, "cards_each is required")
+	}
+	if players > maxDrawCount || payload.CardsEach > maxDrawCount || players*payload.CardsEach > maxDrawCount {
+		return errors.Newf(errors.InvalidInput, "cannot deal more than %d cards", maxDrawCount)
+	}
+	names := payload.Players.Names
+	if names == nil {
+		names = models.PlayerNames(players)
+	}
+	if err := models.ValidatePlayerNames(names); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, err.Error())
+	}
+
+	deal := &models.Deal{DeckID: deckID}
+	event := models.NewDeckEvent(models.EventDealt)
+	deck, err := h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		def, err := h.deckDefinition(deck)
+		if err != nil {
+			return err
+		}
+
+		dealt, left, err := models.DealRoundRobin(players, payload.CardsEach, deck.CardCodes)
+		if err != nil {
+			return errors.Wrap(err, errors.Conflict, err.Error())
+		}
+		deck.CardCodes = left
+
+		piles, err := tx.GetPilesByDeckID(deckID)
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed get piles")
+		}
+		byName := make(map[string]*models.Pile, len(piles))
+		for _, pile := range piles {
+			byName[pile.Name] = pile
+		}
+
+		var dealtCodes []string
+		for i, name := range names {
+			hand := &models.Hand{Player: name, CardCodes: dealt[i]}
+			if hand.Cards, err = buildCards(def, hand.CardCodes); err != nil {
+				return err
+			}
+			deal.Hands = append(deal.Hands, hand)
+			dealtCodes = append(dealtCodes, hand.CardCodes...)
+
+			pile, ok := byName[name]
+			if !ok {
+				pile = &models.Pile{DeckID: deckID, Name: name}
+			}
+			pile.CardCodes = models.PutOnTop(hand.CardCodes, pile.CardCodes)
+			if err = tx.SavePile(pile); err != nil {
+				return errors.Wrap(err, errors.Internal, "failed save pile")
+			}
+		}
+		event.Data.Codes = dealtCodes
+
+		return addDraw(tx, &models.Draw{
+			DeckID:    deck.DeckID,
+			Source:    models.DrawSourceDeck,
+			CardCodes: dealtCodes,
+		})
+	})
+	if err != nil {
+		return repoError(err, "failed deal cards")
+	}
+	deal.Remaining = deck.Remaining
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deal)
+}
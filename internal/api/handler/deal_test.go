@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealCards(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	deck := createTestDeck(t, router, map[string]interface{}{
+		"cards": []string{"AS", "2S", "3S", "4S", "5S", "6S", "7S"},
+	})
+	path := "/v1/deck/" + deck.DeckID
+
+	// deal fails as a whole if deck is short of cards
+	w := doRequest(t, router, http.MethodPost, path+"/deal", map[string]interface{}{"players": []string{"north", "south"}, "cards_each": 4})
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 7.0, decodeObject(t, w)["remaining"])
+	require.Equal(t, 1.0, decodeObject(t, w)["version"])
+	w = doRequest(t, router, http.MethodGet, path+"/pile", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[]`, w.Body.String())
+
+	w = doRequest(t, router, http.MethodPost, path+"/deal", map[string]interface{}{"players": []string{"north", "south"}, "cards_each": 2})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, 3.0, decodeObject(t, w)["remaining"])
+
+	// cards land in named piles, the last dealt card is on top
+	w = doRequest(t, router, http.MethodGet, path+"/pile/north", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []string{"3S", "AS"}, cardCodes(t, w))
+	w = doRequest(t, router, http.MethodGet, path+"/pile/south", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []string{"4S", "2S"}, cardCodes(t, w))
+
+	// cards added to pile follow the same convention
+	w = doRequest(t, router, http.MethodPatch, path+"/cards", map[string]interface{}{"count": 2})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodPost, path+"/pile/north", map[string]interface{}{"codes": []string{"5S", "6S"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodGet, path+"/pile/north", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []string{"6S", "5S", "3S", "AS"}, cardCodes(t, w))
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvablyFairDeck_KeepsCommittedOrder(t *testing.T) {
+	router := newTestRouter(t, Options{UndoPolicy: models.UndoAll})
+
+	deck := createTestDeck(t, router, map[string]interface{}{"provably_fair": true, "client_seed": "lucky"})
+	require.NotNil(t, deck.Commitment)
+	path := "/v1/deck/" + deck.DeckID
+
+	w := doRequest(t, router, http.MethodPatch, path+"/cards", map[string]interface{}{"count": 2})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var drawn models.Cards
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &drawn))
+	require.Len(t, drawn, 2)
+
+	for _, req := range []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{http.MethodPost, path + "/sort", nil},
+		{http.MethodPost, path + "/undo", nil},
+		{http.MethodPost, path + "/shuffle", nil},
+		{http.MethodPost, path + "/return", map[string]interface{}{"codes": []string{drawn[0].Code}, "mode": "top"}},
+		{http.MethodPatch, path + "/cards", map[string]interface{}{"mode": "random", "count": 1}},
+		{http.MethodPatch, path + "/cards", map[string]interface{}{"mode": "position", "positions": []uint{3}}},
+	} {
+		w = doRequest(t, router, req.method, req.path, req.body)
+		require.Equal(t, http.StatusConflict, w.Code, req.path+": "+w.Body.String())
+	}
+
+	w = doRequest(t, router, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 2.0, decodeObject(t, w)["version"])
+
+	w = doRequest(t, router, http.MethodPatch, path+"/cards", map[string]interface{}{"count": 1, "mode": "bottom"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestProvablyFairDeck_HidesOrderTillRevealed(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	w := doRequest(t, router, http.MethodPost, "/v1/deck", map[string]interface{}{"provably_fair": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	created := decodeObject(t, w)
+	require.NotContains(t, created, "cards")
+	path := "/v1/deck/" + created["deck_id"].(string)
+
+	for _, p := range []string{path, path + "?at_event=1", path + "?sort=ace_high"} {
+		w = doRequest(t, router, http.MethodGet, p, nil)
+		require.Equal(t, http.StatusOK, w.Code, p)
+		require.NotContains(t, decodeObject(t, w), "cards", p)
+	}
+	w = doRequest(t, router, http.MethodGet, path+"/peek", nil)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = doRequest(t, router, http.MethodGet, path+"/proof", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, false, decodeObject(t, w)["revealed"])
+
+	// order is shown once deck is closed and proof is revealed
+	w = doRequest(t, router, http.MethodPost, path+"/close", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	for _, p := range []string{path, path + "?at_event=1"} {
+		w = doRequest(t, router, http.MethodGet, p, nil)
+		require.Equal(t, http.StatusOK, w.Code, p)
+		require.Len(t, decodeObject(t, w)["cards"], 52, p)
+	}
+	w = doRequest(t, router, http.MethodGet, path+"/peek", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestProvablyFairDeck_RNG(t *testing.T) {
+	router := newTestRouter(t, Options{RNG: deckhelper.RNGCrypto})
+
+	w := doRequest(t, router, http.MethodPost, "/v1/deck", map[string]interface{}{"provably_fair": true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, deckhelper.RNGFair, decodeObject(t, w)["rng"])
+
+	for _, rng := range []string{deckhelper.RNGMath, deckhelper.RNGCrypto, deckhelper.RNGFair} {
+		w = doRequest(t, router, http.MethodPost, "/v1/deck", map[string]interface{}{"provably_fair": true, "rng": rng})
+		require.Equal(t, http.StatusBadRequest, w.Code, rng)
+	}
+	w = doRequest(t, router, http.MethodPost, "/v1/deck", map[string]interface{}{"rng": deckhelper.RNGFair})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
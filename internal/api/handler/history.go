@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/internal/repository"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+	"github.com/go-chi/chi/v5"
+)
+
+// Limits of history page
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// GetDrawHistory returns page of deck draws ordered by batch,
+// page is set by limit and offset query parameters
+// Route /v1/deck/{deckID}/history [get]
+func (h *CardGameHandler) GetDrawHistory(w http.ResponseWriter, r *http.Request) error {
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		return err
+	}
+
+	deck, err := h.repo.GetDeckByID(deckID)
+	if err != nil {
+		return repoError(err, "failed get deck")
+	}
+	def, err := h.deckDefinition(deck)
+	if err != nil {
+		return err
+	}
+
+	draws, total, err := h.repo.GetDraws(deckID, limit, offset)
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed get draw history")
+	}
+	for _, draw := range draws {
+		if draw.Cards, err = buildCards(def, draw.CardCodes); err != nil {
+			return err
+		}
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, &models.DrawHistory{
+		DeckID: deckID,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Draws:  draws,
+	})
+}
+
+// addDraw stores draw in deck history
+func addDraw(tx *repository.Repository, draw *models.Draw) error {
+	// TODO: set requester once authentication is added
+	if err := tx.AddDraw(draw); err != nil {
+		return errors.Wrap(err, errors.Internal, "failed store draw history")
+	}
+	return nil
+}
+
+// pageParams returns limit and offset query params
+func pageParams(r *http.Request) (uint, uint, error) {
+	limit, offset := uint(defaultHistoryLimit), uint(0)
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || parsed == 0 || parsed > maxHistoryLimit {
+			return 0, 0, errors.Newf(errors.InvalidInput, "limit should be from 1 to %d", maxHistoryLimit)
+		}
+		limit = uint(parsed)
+	}
+	if value := query.Get("offset"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return 0, 0, errors.New(errors.InvalidInput, "offset should be non-negative number")
+		}
+		offset = uint(parsed)
+	}
+
+	return limit, offset, nil
+}
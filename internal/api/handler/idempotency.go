@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// idempotentTimeout is the longest time request with idempotency key may change data,
+	// its transactions are rolled back after it
+	idempotentTimeout = time.Minute
+	// idempotencyLease is how long request may hold key in progress,
+	// key is taken over by retry after it, e.g. if process died while handling request.
+	// Lease is longer than idempotentTimeout, so request which key is taken over
+	// cannot change data anymore
+	idempotencyLease = 5 * idempotentTimeout
+	// maxAcquireAttempts is how many times key is acquired
+	// if it disappears before stored response is read
+	maxAcquireAttempts = 3
+)
+
+// replayedHeaders are response headers stored along with response body
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+// idempotent wraps handler to store the first response per Idempotency-Key header
+// and replay it on retries, requests without the header are passed as is.
+// Key reused with different request gets conflict error.
+func (h *CardGameHandler) idempotent(next httphelper.Handler) httphelper.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		keyName := r.Header.Get(idempotencyKeyHeader)
+		if keyName == "" {
+			return next(w, r)
+		}
+		if len(keyName) > maxIdempotencyKeyLength {
+			return errors.Newf(errors.InvalidInput, "%s cannot be longer than %d", idempotencyKeyHeader, maxIdempotencyKeyLength)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return errors.InvalidInput.Wrap(err, "the request body is missing")
+		}
+		_ = r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		key := &models.IdempotencyKey{
+			// TODO: set client ID once authentication is added
+			ClientID:    "",
+			Key:         keyName,
+			RequestHash: requestHash(r, body),
+		}
+
+		for attempt := 1; ; attempt++ {
+			acquired, err := h.repo.AcquireIdempotencyKey(key, h.opts.IdempotencyTTL, idempotencyLease)
+			if err != nil {
+				return errors.Wrap(err, errors.Internal, "failed store idempotency key")
+			}
+			if acquired {
+				break
+			}
+
+			// key may be released or expire between acquire and read, it is acquired again then
+			stored, err := h.repo.GetIdempotencyKey(key.ClientID, key.Key)
+			if err == nil {
+				return h.replay(w, key, stored)
+			}
+			if e, ok := err.(*errors.Error); !ok || e.Kind() != errors.NotFound {
+				return repoError(err, "failed get idempotency key")
+			}
+			if attempt == maxAcquireAttempts {
+				return errors.Newf(errors.Conflict, "request with the same %s is in progress", idempotencyKeyHeader)
+			}
+		}
+
+		ctx, cancel := context.WithDeadline(r.Context(), key.CreatedAt.Add(idempotentTimeout))
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		// key is released if handler panics, so request can be retried with the same key
+		defer func() {
+			if p := recover(); p != nil {
+				if err := h.repo.DeleteIdempotencyKey(key); err != nil {
+					h.logger.Error("failed delete idempotency key", zap.String("error", err.Error()))
+				}
+				panic(p)
+			}
+		}()
+
+		rec := httphelper.NewResponseRecorder(w)
+		httphelper.Handler(next).ServeHTTP(rec, r)
+
+		// server errors are not stored, so request can be retried with the same key
+		if rec.Status >= http.StatusInternalServerError {
+			if err := h.repo.DeleteIdempotencyKey(key); err != nil {
+				h.logger.Error("failed delete idempotency key", zap.String("error", err.Error()))
+			}
+			return nil
+		}
+
+		headers := http.Header{}
+		for _, name := range replayedHeaders {
+			if value := rec.Header().Get(name); value != "" {
+				headers.Set(name, value)
+			}
+		}
+		key.Status = rec.Status
+		key.Body = rec.Body.Bytes()
+		if key.Headers, err = json.Marshal(headers); err != nil {
+			h.logger.Error("failed marshal response headers", zap.String("error", err.Error()))
+			return nil
+		}
+		if err := h.repo.CompleteIdempotencyKey(key); err != nil {
+			h.logger.Error("failed store idempotent response", zap.String("error", err.Error()))
+		}
+
+		return nil
+	}
+}
+
+// replay writes stored response for already used idempotency key
+func (h *CardGameHandler) replay(w http.ResponseWriter, key, stored *models.IdempotencyKey) error {
+	if stored.RequestHash != key.RequestHash {
+		return errors.Newf(errors.Conflict, "%s has been already used with different request", idempotencyKeyHeader)
+	}
+	if !stored.IsCompleted() {
+		return errors.Newf(errors.Conflict, "request with the same %s is in progress", idempotencyKeyHeader)
+	}
+
+	var headers http.Header
+	if err := json.Unmarshal(stored.Headers, &headers); err != nil {
+		return errors.Wrap(err, errors.Internal, "failed read stored response")
+	}
+	for name := range headers {
+		w.Header().Set(name, headers.Get(name))
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	if _, err := w.Write(stored.Body); err != nil {
+		return errors.Wrap(err, errors.Internal, "failed to write the response body")
+	}
+
+	return nil
+}
+
+// requestHash returns fingerprint of request method, path and body
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
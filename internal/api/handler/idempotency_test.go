@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotent_ReleasesKeyOnPanic(t *testing.T) {
+	h := newTestHandler(t, Options{IdempotencyTTL: time.Hour})
+	keyName := uuid.NewV4().String()
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/v1/deck", strings.NewReader(`{}`))
+		req.Header.Set(idempotencyKeyHeader, keyName)
+		return req
+	}
+
+	panicking := h.idempotent(func(http.ResponseWriter, *http.Request) error {
+		panic("boom")
+	})
+	require.PanicsWithValue(t, "boom", func() {
+		_ = panicking(httptest.NewRecorder(), newRequest())
+	})
+
+	// retry with the same key is handled instead of being reported as in progress
+	handled := false
+	w := httptest.NewRecorder()
+	err := h.idempotent(func(w http.ResponseWriter, _ *http.Request) error {
+		handled = true
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})(w, newRequest())
+	require.NoError(t, err)
+	require.True(t, handled)
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
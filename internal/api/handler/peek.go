@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/internal/repository"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+	"github.com/go-chi/chi/v5"
+)
+
+// PeekCards returns [N] cards from the top or the bottom of deck by it's ID (one by default)
+// without drawing them, the bottom card goes first if peeked from the bottom.
+// Peek is recorded in deck history, it is not allowed for decks created with no_peek
+// and for provably fair decks till their order is revealed
+// Route /v1/deck/{deckID}/peek [get]
+func (h *CardGameHandler) PeekCards(w http.ResponseWriter, r *http.Request) error {
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	query := r.URL.Query()
+	count := uint64(1)
+	if value := query.Get("count"); value != "" {
+		var err error
+		count, err = strconv.ParseUint(value, 10, 32)
+		if err != nil || count == 0 || count > maxDrawCount {
+			return errors.Newf(errors.InvalidInput, "count should be from 1 to %d", maxDrawCount)
+		}
+	}
+	from := models.DrawMode(query.Get("from"))
+	switch from {
+	case "", models.DrawModeTop, models.DrawModeBottom:
+	default:
+		return errors.Newf(errors.InvalidInput, "unknown peek position %q, use %q or %q", from, models.DrawModeTop, models.DrawModeBottom)
+	}
+
+	var cards models.Cards
+	deck, err := h.repo.WithContext(r.Context()).LockDeck(deckID, func(tx *repository.Repository, deck *models.Deck) error {
+		if deck.HidesOrder() {
+			return errors.New(errors.Conflict, "peek is disabled for deck")
+		}
+		if len(deck.CardCodes) == 0 {
+			return errors.New(errors.InvalidInput, "deck remaining 0 cards")
+		}
+
+		def, err := h.deckDefinition(deck)
+		if err != nil {
+			return err
+		}
+
+		n := uint(count)
+		if uint(len(deck.CardCodes)) < n {
+			n = uint(len(deck.CardCodes))
+		}
+		var codes []string
+		if from == models.DrawModeBottom {
+			codes, err = models.DrawBottomNCards(n, deck.CardCodes)
+		} else {
+			codes, err = models.DrawTopNCards(n, deck.CardCodes)
+		}
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed peek cards")
+		}
+
+		if cards, err = buildCards(def, codes); err != nil {
+			return err
+		}
+
+		return addDraw(tx, &models.Draw{
+			DeckID:    deck.DeckID,
+			Kind:      models.DrawKindPeek,
+			Source:    models.DrawSourceDeck,
+			CardCodes: codes,
+		})
+	})
+	if err != nil {
+		return repoError(err, "failed peek cards")
+	}
+	w.Header().Set("ETag", httphelper.ETag(deck.Version))
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, cards)
+}
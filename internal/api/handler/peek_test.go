@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoPeekDeck_HidesOrder(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	deck := createTestDeck(t, router, map[string]interface{}{"is_shuffled": true, "no_peek": true})
+	require.True(t, deck.NoPeek)
+
+	for _, path := range []string{
+		"/v1/deck/" + deck.DeckID,
+		"/v1/deck/" + deck.DeckID + "?sort=ace_high",
+		"/v1/deck/" + deck.DeckID + "?at_event=1",
+	} {
+		w := doRequest(t, router, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code, path)
+		opened := decodeObject(t, w)
+		require.NotContains(t, opened, "cards", path)
+		require.Equal(t, 52.0, opened["remaining"], path)
+	}
+
+	w := doRequest(t, router, http.MethodPost, "/v1/deck/"+deck.DeckID+"/sort", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	sorted := decodeObject(t, w)
+	require.NotContains(t, sorted, "cards")
+	require.Equal(t, 2.0, sorted["version"])
+
+	w = doRequest(t, router, http.MethodGet, "/v1/deck/"+deck.DeckID+"/peek", nil)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	// cards of other decks are shown
+	deck = createTestDeck(t, router, map[string]interface{}{"is_shuffled": true})
+	w = doRequest(t, router, http.MethodGet, "/v1/deck/"+deck.DeckID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, decodeObject(t, w)["cards"], 52)
+}
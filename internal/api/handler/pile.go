@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	apimodels "github.com/card-deck/internal/api/models"
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+
+	"github.com/card-deck/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// ListPiles returns all piles attached to deck by it's ID
+// Route /v1/deck/{deckID}/pile [get]
+func (h *CardGameHandler) ListPiles(w http.ResponseWriter, r *http.Request) error {
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	if _, err := h.repo.GetDeckByID(deckID); err != nil {
+		return errors.New(errors.NotFound, "deck not found")
+	}
+
+	piles, err := h.repo.GetPilesByDeckID(deckID)
+	if err != nil {
+		return errors.New(errors.Internal, "failed get piles")
+	}
+	if piles == nil {
+		piles = []*models.Pile{}
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, piles)
+}
+
+// OpenPile returns all cards into pile by deck ID and pile name
+// Route /v1/deck/{deckID}/pile/{name} [get]
+func (h *CardGameHandler) OpenPile(w http.ResponseWriter, r *http.Request) error {
+	deckID, name, err := pileParams(r)
+	if err != nil {
+		return err
+	}
+
+	deck, err := h.repo.GetDeckByID(deckID)
+	if err != nil {
+		return repoError(err, "failed get deck")
+	}
+
+	pile, err := h.repo.GetPile(deckID, name)
+	if err != nil {
+		return repoError(err, "failed get pile")
+	}
+
+	def, err := h.deckDefinition(deck)
+	if err != nil {
+		return err
+	}
+	cards, err := buildCards(def, pile.CardCodes)
+	if err != nil {
+		return err
+	}
+	pile.Cards = cards
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, pile)
+}
+
+// AddToPile puts drawn cards on top of the pile one by one, so the last given card
+// becomes the top one as if cards were dealt. Pile is created if not exists
+// Route /v1/deck/{deckID}/pile/{name} [post]
+func (h *CardGameHandler) AddToPile(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.AddToPileRequest
+
+	deckID, name, err := pileParams(r)
+	if err != nil {
+		return err
+	}
+
+	if err := httphelper.ReadJSON(r, &payload); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+	}
+
+	if len(payload.Codes) == 0 {
+		return errors.New(errors.InvalidInput, "codes is required")
+	}
+
+	pile := &models.Pile{
+		DeckID: deckID,
+		Name:   name,
+	}
+	event := models.NewDeckEvent(models.EventMovedToPile)
+	event.Data.Codes = payload.Codes
+	event.Data.Pile = name
+	_, err = h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		piles, err := tx.GetPilesByDeckID(deckID)
+		if err != nil {
+			return errors.New(errors.Internal, "failed get piles")
+		}
+
+		if foreign := models.MissingCodes(payload.Codes, deck.OriginalCodes); len(foreign) > 0 {
+			return errors.Newf(errors.InvalidInput, "cards never belonged to deck: %s", strings.Join(foreign, ", "))
+		}
+		if notDrawn := models.ExcessCodes(payload.Codes, drawnLooseCodes(deck, piles)); len(notDrawn) > 0 {
+			return errors.Newf(errors.Conflict, "cards are not drawn from deck or held in pile: %s", strings.Join(notDrawn, ", "))
+		}
+
+		for _, p := range piles {
+			if p.Name == name {
+				pile = p
+				break
+			}
+		}
+		pile.CardCodes = models.PutOnTop(payload.Codes, pile.CardCodes)
+
+		if err = tx.SavePile(pile); err != nil {
+			return errors.New(errors.Internal, "failed add cards to pile")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return repoError(err, "failed add cards to pile")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, pile)
+}
+
+// ShufflePile shuffles cards into pile by deck ID and pile name
+// Route /v1/deck/{deckID}/pile/{name}/shuffle [post]
+func (h *CardGameHandler) ShufflePile(w http.ResponseWriter, r *http.Request) error {
+	deckID, name, err := pileParams(r)
+	if err != nil {
+		return err
+	}
+
+	var pile *models.Pile
+	event := models.NewDeckEvent(models.EventPileShuffled)
+	event.Data.Pile = name
+	_, err = h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		p, err := tx.GetPile(deckID, name)
+		if err != nil {
+			return err
+		}
+		pile = p
+
+		deckhelper.Shuffle(pile.CardCodes, deck.Random())
+
+		return tx.SavePile(pile)
+	})
+	if err != nil {
+		return repoError(err, "failed shuffle pile")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, pile)
+}
+
+// DrawFromPile draws [N] cards from pile by deck ID and pile name,
+// supports the same modes as DrawCards
+// Route /v1/deck/{deckID}/pile/{name}/cards [patch]
+func (h *CardGameHandler) DrawFromPile(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.DrawCardsRequest
+
+	deckID, name, err := pileParams(r)
+	if err != nil {
+		return err
+	}
+
+	if err := httphelper.ReadJSON(r, &payload); err != nil {
+		return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+	}
+
+	if err := validateDrawRequest(&payload); err != nil {
+		return err
+	}
+
+	var cards models.Cards
+	event := models.NewDeckEvent(models.EventDrawn)
+	event.Data.Pile = name
+	_, err = h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		pile, err := tx.GetPile(deckID, name)
+		if err != nil {
+			return err
+		}
+
+		def, err := h.deckDefinition(deck)
+		if err != nil {
+			return err
+		}
+
+		if err = reseedDeck(deck, payload.Seed); err != nil {
+			return err
+		}
+		drawnCodes, leftCodes, err := drawCodes(&payload, def, "pile", pile.CardCodes, deck.Random)
+		if err != nil {
+			return err
+		}
+		event.Data.Codes = drawnCodes
+
+		cards, err = buildCards(def, drawnCodes)
+		if err != nil {
+			return err
+		}
+
+		pile.CardCodes = leftCodes
+
+		if err = tx.SavePile(pile); err != nil {
+			return err
+		}
+
+		return addDraw(tx, &models.Draw{
+			DeckID:    deck.DeckID,
+			Source:    models.DrawSourcePile,
+			Pile:      &name,
+			CardCodes: drawnCodes,
+		})
+	})
+	if err != nil {
+		return repoError(err, "failed draw cards from pile")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, cards)
+}
+
+// pileParams returns deckID and pile name URL params
+func pileParams(r *http.Request) (string, string, error) {
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return "", "", errors.New(errors.InvalidInput, "deckID is required")
+	}
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return "", "", errors.New(errors.InvalidInput, "pile name is required")
+	}
+
+	return deckID, name, nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestPile creates unshuffled deck of given cards, draws them all
+// and puts them into pile of given name, so the pile holds cards in the given order
+func createTestPile(t *testing.T, router http.Handler, deckBody map[string]interface{}, name string) string {
+	deck := createTestDeck(t, router, deckBody)
+	path := "/v1/deck/" + deck.DeckID
+
+	codes := deckBody["cards"].([]string)
+	w := doRequest(t, router, http.MethodPatch, path+"/cards", map[string]interface{}{"count": len(codes)})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	reversed := make([]string, 0, len(codes))
+	for i := len(codes) - 1; i >= 0; i-- {
+		reversed = append(reversed, codes[i])
+	}
+	w = doRequest(t, router, http.MethodPost, path+"/pile/"+name, map[string]interface{}{"codes": reversed})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	return path
+}
+
+func TestAddToPile(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	deck := createTestDeck(t, router, map[string]interface{}{"cards": []string{"AS", "2S", "3S"}})
+	path := "/v1/deck/" + deck.DeckID
+
+	// cards still in deck cannot be added to pile
+	w := doRequest(t, router, http.MethodPost, path+"/pile/hand", map[string]interface{}{"codes": []string{"AS"}})
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodPost, path+"/pile/hand", map[string]interface{}{"codes": []string{"KD"}})
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodPost, path+"/pile/hand", map[string]interface{}{"codes": []string{}})
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = doRequest(t, router, http.MethodPatch, path+"/cards", map[string]interface{}{"count": 1})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodPost, path+"/pile/hand", map[string]interface{}{"codes": []string{"AS"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, 1.0, decodeObject(t, w)["remaining"])
+
+	// card held in pile cannot be added again
+	w = doRequest(t, router, http.MethodPost, path+"/pile/discard", map[string]interface{}{"codes": []string{"AS"}})
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
+
+func TestListPiles_OpenPile(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	path := createTestPile(t, router, map[string]interface{}{"cards": []string{"AS", "2S", "3S"}}, "hand")
+
+	w := doRequest(t, router, http.MethodGet, path+"/pile", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var piles []*models.Pile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &piles))
+	require.Len(t, piles, 1)
+	require.Equal(t, "hand", piles[0].Name)
+	require.Equal(t, uint(3), piles[0].Remaining)
+
+	w = doRequest(t, router, http.MethodGet, path+"/pile/hand", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []string{"AS", "2S", "3S"}, cardCodes(t, w))
+
+	w = doRequest(t, router, http.MethodGet, path+"/pile/discard", nil)
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	w = doRequest(t, router, http.MethodGet, "/v1/deck/unknown/pile", nil)
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestShufflePile(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	codes := []string{"AS", "2S", "3S", "4S", "5S", "6S", "7S", "8S"}
+	path := createTestPile(t, router, map[string]interface{}{"cards": codes, "seed": 5}, "hand")
+
+	w := doRequest(t, router, http.MethodPost, path+"/pile/hand/shuffle", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// seeded deck shuffles pile by the first step of its random sequence
+	expected := append([]string(nil), codes...)
+	deckhelper.Shuffle(expected, deckhelper.NewSeededRandom(5, 0))
+	w = doRequest(t, router, http.MethodGet, path+"/pile/hand", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, expected, cardCodes(t, w))
+
+	w = doRequest(t, router, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 4.0, decodeObject(t, w)["version"])
+
+	w = doRequest(t, router, http.MethodPost, path+"/pile/discard/shuffle", nil)
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestDrawFromPile(t *testing.T) {
+	router := newTestRouter(t, Options{})
+
+	codes := []string{"AS", "2S", "3S", "4S", "5S", "6S"}
+	path := createTestPile(t, router, map[string]interface{}{"cards": codes, "seed": 9}, "hand")
+
+	draw := func(body map[string]interface{}) []string {
+		w := doRequest(t, router, http.MethodPatch, path+"/pile/hand/cards", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var cards models.Cards
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cards))
+		drawn := make([]string, 0, len(cards))
+		for _, card := range cards {
+			drawn = append(drawn, card.Code)
+		}
+		return drawn
+	}
+
+	require.Equal(t, []string{"AS", "2S"}, draw(map[string]interface{}{"count": 2}))
+	require.Equal(t, []string{"6S"}, draw(map[string]interface{}{"count": 1, "mode": "bottom"}))
+
+	random := draw(map[string]interface{}{"count": 2, "mode": "random"})
+	require.Len(t, random, 2)
+	require.Subset(t, []string{"3S", "4S", "5S"}, random)
+
+	w := doRequest(t, router, http.MethodGet, path+"/pile/hand", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	left := cardCodes(t, w)
+	require.Len(t, left, 1)
+	require.ElementsMatch(t, []string{"3S", "4S", "5S"}, append(left, random...))
+
+	// count more than remaining draws the rest of pile
+	require.Equal(t, []string{left[0]}, draw(map[string]interface{}{"count": 5}))
+	w = doRequest(t, router, http.MethodPatch, path+"/pile/hand/cards", map[string]interface{}{"count": 1})
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+
+	apimodels "github.com/card-deck/internal/api/models"
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateTemplate creates new user-defined deck template
+// Route /v1/template [post]
+func (h *CardGameHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) error {
+	template, err := readTemplate(r)
+	if err != nil {
+		return err
+	}
+
+	if err = h.repo.WithContext(r.Context()).CreateTemplate(template); err != nil {
+		return errors.Wrap(err, errors.Internal, "failed store new template")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, template)
+}
+
+// ListTemplates returns all deck templates
+// Route /v1/template [get]
+func (h *CardGameHandler) ListTemplates(w http.ResponseWriter, _ *http.Request) error {
+	templates, err := h.repo.GetTemplates()
+	if err != nil {
+		return errors.Wrap(err, errors.Internal, "failed get templates")
+	}
+	if templates == nil {
+		templates = []*models.Template{}
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, templates)
+}
+
+// GetTemplate returns deck template by it's ID
+// Route /v1/template/{templateID} [get]
+func (h *CardGameHandler) GetTemplate(w http.ResponseWriter, r *http.Request) error {
+	templateID := chi.URLParam(r, "templateID")
+	if templateID == "" {
+		return errors.New(errors.InvalidInput, "templateID is required")
+	}
+
+	template, err := h.repo.GetTemplateByID(templateID)
+	if err != nil {
+		return repoError(err, "failed get template")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, template)
+}
+
+// UpdateTemplate replaces name and cards of deck template by it's ID,
+// template cannot be changed once any deck is created from it
+// Route /v1/template/{templateID} [put]
+func (h *CardGameHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) error {
+	templateID := chi.URLParam(r, "templateID")
+	if templateID == "" {
+		return errors.New(errors.InvalidInput, "templateID is required")
+	}
+
+	template, err := readTemplate(r)
+	if err != nil {
+		return err
+	}
+	template.TemplateID = templateID
+
+	if err = h.repo.WithContext(r.Context()).UpdateTemplate(template); err != nil {
+		return repoError(err, "failed update template")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, template)
+}
+
+// DeleteTemplate removes deck template by it's ID,
+// template cannot be removed once any deck is created from it
+// Route /v1/template/{templateID} [delete]
+func (h *CardGameHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) error {
+	templateID := chi.URLParam(r, "templateID")
+	if templateID == "" {
+		return errors.New(errors.InvalidInput, "templateID is required")
+	}
+
+	if err := h.repo.WithContext(r.Context()).DeleteTemplate(templateID); err != nil {
+		return repoError(err, "failed delete template")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// readTemplate reads and validates template from request body
+func readTemplate(r *http.Request) (*models.Template, error) {
+	var payload apimodels.TemplateRequest
+
+	if err := httphelper.ReadJSON(r, &payload); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+	}
+
+	template := &models.Template{
+		Name:  payload.Name,
+		Cards: payload.Cards,
+	}
+	if _, err := template.Definition(); err != nil {
+		return nil, errors.Wrap(err, errors.InvalidInput, err.Error())
+	}
+
+	return template, nil
+}
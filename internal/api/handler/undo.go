@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	apimodels "github.com/card-deck/internal/api/models"
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/internal/repository"
+	"github.com/card-deck/pkg/errors"
+	httphelper "github.com/card-deck/pkg/http"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxUndoSteps is a maximum count of operations undone at once
+const maxUndoSteps = 100
+
+// UndoDeck reverts the latest [N] operations of deck by it's ID (one by default),
+// cards of deck and piles are restored from deck events. Random sequence of seeded deck
+// is not rewound and draw history keeps undone draws.
+// Undo of competitive decks is allowed by undo policy only
+// Route /v1/deck/{deckID}/undo [post]
+func (h *CardGameHandler) UndoDeck(w http.ResponseWriter, r *http.Request) error {
+	var payload apimodels.UndoRequest
+
+	deckID := chi.URLParam(r, "deckID")
+	if deckID == "" {
+		return errors.New(errors.InvalidInput, "deckID is required")
+	}
+
+	if r.ContentLength != 0 {
+		if err := httphelper.ReadJSON(r, &payload); err != nil {
+			return errors.Wrap(err, errors.InvalidInput, "failed parse payload")
+		}
+	}
+	if payload.Steps == 0 {
+		payload.Steps = 1
+	}
+	if payload.Steps > maxUndoSteps {
+		return errors.Newf(errors.InvalidInput, "steps cannot be more than %d", maxUndoSteps)
+	}
+
+	event := models.NewDeckEvent(models.EventUndone)
+	deck, err := h.modifyDeck(w, r, deckID, event, func(tx *repository.Repository, deck *models.Deck) error {
+		if deck.IsProvablyFair() {
+			return errors.New(errors.Conflict, "operations of provably fair deck cannot be undone")
+		}
+		if !h.opts.UndoPolicy.Allows(deck) {
+			if deck.IsCompetitive {
+				return errors.New(errors.Conflict, "undo is disabled for competitive decks")
+			}
+			return errors.New(errors.Conflict, "undo is disabled")
+		}
+
+		events, err := tx.GetDeckEvents(deckID, deck.Version)
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed get deck events")
+		}
+		target, err := models.UndoTarget(events, payload.Steps)
+		if err != nil {
+			return errors.Wrap(err, errors.Conflict, err.Error())
+		}
+		restored, piles, err := models.ReplayDeck(deck, events, target)
+		if err != nil {
+			return errors.Wrap(err, errors.Internal, "failed replay deck events")
+		}
+		event.Data.UndoneTo = target
+
+		deck.CardCodes = restored.CardCodes
+		deck.IsShuffled = restored.IsShuffled
+
+		if err = tx.DeletePilesByDeckID(deckID); err != nil {
+			return errors.Wrap(err, errors.Internal, "failed restore piles")
+		}
+		for _, pile := range piles {
+			if err = tx.SavePile(pile); err != nil {
+				return errors.Wrap(err, errors.Internal, "failed restore piles")
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return repoError(err, "failed undo deck operations")
+	}
+
+	return httphelper.WriteSuccessResponse(w, http.StatusOK, deck)
+}
@@ -1,14 +1,106 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/card-deck/internal/models"
+)
+
 // CreateNewDeckRequest represents type for
 // request body on creating new Deck
 type CreateNewDeckRequest struct {
-	IsShuffled bool     `json:"is_shuffled,omitempty"`
-	Cards      []string `json:"cards,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	TemplateID   string   `json:"template_id,omitempty"`
+	IsShuffled   bool     `json:"is_shuffled,omitempty"`
+	Cards        []string `json:"cards,omitempty"`
+	Jokers       *uint    `json:"jokers,omitempty"`
+	DecksCount   uint     `json:"decks_count,omitempty"`
+	RNG          string   `json:"rng,omitempty"`
+	Seed         *int64   `json:"seed,omitempty"`
+	ProvablyFair bool     `json:"provably_fair,omitempty"`
+	ClientSeed   string   `json:"client_seed,omitempty"`
+	Competitive  bool     `json:"competitive,omitempty"`
+	NoPeek       bool     `json:"no_peek,omitempty"`
 }
 
 // DrawCardsRequest represents type for
 // request body on draw card(s)
 type DrawCardsRequest struct {
-	Count uint `json:"count,omitempty"`
+	Count     uint            `json:"count,omitempty"`
+	Mode      models.DrawMode `json:"mode,omitempty"`
+	Positions []uint          `json:"positions,omitempty"`
+	Codes     []string        `json:"codes,omitempty"`
+	Seed      *int64          `json:"seed,omitempty"`
+}
+
+// ReturnCardsRequest represents type for
+// request body on return card(s) into deck
+type ReturnCardsRequest struct {
+	Codes []string          `json:"codes,omitempty"`
+	Mode  models.ReturnMode `json:"mode,omitempty"`
+}
+
+// ShuffleDeckRequest represents type for
+// request body on shuffle deck
+type ShuffleDeckRequest struct {
+	IncludeDrawn bool   `json:"include_drawn,omitempty"`
+	Seed         *int64 `json:"seed,omitempty"`
+	Method       string `json:"method,omitempty"`
+	Times        uint   `json:"times,omitempty"`
+}
+
+// SortCardsRequest represents type for
+// request body on sorting deck cards
+type SortCardsRequest struct {
+	Sort  models.SortOrder `json:"sort,omitempty"`
+	Suits []string         `json:"suits,omitempty"`
+	Trump string           `json:"trump,omitempty"`
+}
+
+// DealRequest represents type for
+// request body on dealing cards to players
+type DealRequest struct {
+	Players   DealPlayers `json:"players"`
+	CardsEach uint        `json:"cards_each"`
+}
+
+// DealPlayers represents players of deal given
+// either by count or by list of names
+type DealPlayers struct {
+	Count uint
+	Names []string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// players are given by number or by array of names
+func (p *DealPlayers) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &p.Count); err == nil {
+		p.Names = nil
+		return nil
+	}
+	if err := json.Unmarshal(data, &p.Names); err != nil {
+		return fmt.Errorf("players should be count or list of names")
+	}
+	p.Count = uint(len(p.Names))
+	return nil
+}
+
+// UndoRequest represents type for
+// request body on undo of deck operations
+type UndoRequest struct {
+	Steps uint `json:"steps,omitempty"`
+}
+
+// AddToPileRequest represents type for
+// request body on adding drawn card(s) to pile
+type AddToPileRequest struct {
+	Codes []string `json:"codes,omitempty"`
+}
+
+// TemplateRequest represents type for
+// request body on creating or updating deck template
+type TemplateRequest struct {
+	Name  string                `json:"name"`
+	Cards []models.TemplateCard `json:"cards"`
 }
@@ -12,4 +12,7 @@ app {
   listening = 8083
   prod = true
   disableStacktrace = true
+  idempotencyTTL = "1h"
+  rng = "crypto"
+  undo = "none"
 }
\ No newline at end of file
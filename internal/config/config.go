@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+
 	// required for database
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -21,6 +25,7 @@ import (
 type Config struct {
 	Database Database `hcl:"db,block"`
 	App      App      `hcl:"app,block"`
+	Decks    []Deck   `hcl:"deck,block"`
 }
 
 // Database represents Postgres configuration
@@ -34,11 +39,17 @@ type Database struct {
 	SSLMode        string `hcl:"sslmode"`
 }
 
+// DefaultIdempotencyTTL is used when idempotencyTTL is not configured
+const DefaultIdempotencyTTL = 24 * time.Hour
+
 // App represents general application configuration
 type App struct {
-	Listening         int  `hcl:"listening"`
-	Prod              bool `hcl:"prod"`
-	DisableStacktrace bool `hcl:"disableStacktrace"`
+	Listening         int    `hcl:"listening"`
+	Prod              bool   `hcl:"prod"`
+	DisableStacktrace bool   `hcl:"disableStacktrace"`
+	IdempotencyTTL    string `hcl:"idempotencyTTL,optional"`
+	RNG               string `hcl:"rng,optional"`
+	Undo              string `hcl:"undo,optional"`
 }
 
 // NewConfig reads configuration from given config path
@@ -102,3 +113,42 @@ func (a *App) InitLogger() (*zap.Logger, error) {
 
 	return log, nil
 }
+
+// DefaultRNG returns random generator used by decks created without explicit one
+func (a *App) DefaultRNG() (string, error) {
+	if a.RNG == "" {
+		return deckhelper.RNGMath, nil
+	}
+	if !deckhelper.IsValidRNG(a.RNG) {
+		return "", fmt.Errorf("unknown rng %q, use %q or %q", a.RNG, deckhelper.RNGMath, deckhelper.RNGCrypto)
+	}
+	return a.RNG, nil
+}
+
+// UndoPolicy returns which decks are allowed to undo their operations,
+// undo is disabled for competitive decks by default
+func (a *App) UndoPolicy() (models.UndoPolicy, error) {
+	if a.Undo == "" {
+		return models.UndoCasual, nil
+	}
+	policy := models.UndoPolicy(a.Undo)
+	if !policy.IsValid() {
+		return "", fmt.Errorf("unknown undo policy %q, use %q, %q or %q", a.Undo, models.UndoAll, models.UndoCasual, models.UndoNone)
+	}
+	return policy, nil
+}
+
+// IdempotencyWindow returns how long responses are stored for idempotency keys
+func (a *App) IdempotencyWindow() (time.Duration, error) {
+	if a.IdempotencyTTL == "" {
+		return DefaultIdempotencyTTL, nil
+	}
+	ttl, err := time.ParseDuration(a.IdempotencyTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid idempotencyTTL: %v", err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("idempotencyTTL should be positive")
+	}
+	return ttl, nil
+}
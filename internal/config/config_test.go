@@ -2,8 +2,12 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -26,13 +30,19 @@ func TestNewConfig(t *testing.T) {
 			SSLMode:        "disable",
 		},
 		App: struct {
-			Listening         int  `hcl:"listening"`
-			Prod              bool `hcl:"prod"`
-			DisableStacktrace bool `hcl:"disableStacktrace"`
+			Listening         int    `hcl:"listening"`
+			Prod              bool   `hcl:"prod"`
+			DisableStacktrace bool   `hcl:"disableStacktrace"`
+			IdempotencyTTL    string `hcl:"idempotencyTTL,optional"`
+			RNG               string `hcl:"rng,optional"`
+			Undo              string `hcl:"undo,optional"`
 		}{
 			Listening:         8083,
 			Prod:              true,
 			DisableStacktrace: true,
+			IdempotencyTTL:    "1h",
+			RNG:               "crypto",
+			Undo:              "none",
 		},
 	}
 
@@ -55,3 +65,50 @@ func TestDatabase_connectionURL(t *testing.T) {
 
 	require.Equal(t, expectedURL, db.connectionURL())
 }
+
+func TestApp_IdempotencyWindow(t *testing.T) {
+	var (
+		ttl time.Duration
+		err error
+	)
+
+	ttl, err = (&App{}).IdempotencyWindow()
+	require.NoError(t, err)
+	require.Equal(t, DefaultIdempotencyTTL, ttl)
+
+	ttl, err = (&App{IdempotencyTTL: "30m"}).IdempotencyWindow()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, ttl)
+
+	_, err = (&App{IdempotencyTTL: "day"}).IdempotencyWindow()
+	require.Error(t, err)
+
+	_, err = (&App{IdempotencyTTL: "-1h"}).IdempotencyWindow()
+	require.EqualError(t, err, "idempotencyTTL should be positive")
+}
+
+func TestApp_DefaultRNG(t *testing.T) {
+	rng, err := (&App{}).DefaultRNG()
+	require.NoError(t, err)
+	require.Equal(t, deckhelper.RNGMath, rng)
+
+	rng, err = (&App{RNG: "crypto"}).DefaultRNG()
+	require.NoError(t, err)
+	require.Equal(t, deckhelper.RNGCrypto, rng)
+
+	_, err = (&App{RNG: "dice"}).DefaultRNG()
+	require.EqualError(t, err, `unknown rng "dice", use "math" or "crypto"`)
+}
+
+func TestApp_UndoPolicy(t *testing.T) {
+	policy, err := (&App{}).UndoPolicy()
+	require.NoError(t, err)
+	require.Equal(t, models.UndoCasual, policy)
+
+	policy, err = (&App{Undo: "all"}).UndoPolicy()
+	require.NoError(t, err)
+	require.Equal(t, models.UndoAll, policy)
+
+	_, err = (&App{Undo: "sometimes"}).UndoPolicy()
+	require.EqualError(t, err, `unknown undo policy "sometimes", use "all", "casual" or "none"`)
+}
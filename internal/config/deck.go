@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+)
+
+// Deck represents custom deck definition declared by deck block, e.g.
+//
+//	deck "short" {
+//	  ranks = ["A", "K", "Q", "J", "10", "9", "8", "7", "6"]
+//	  suits = ["S", "D", "C", "H"]
+//	}
+//
+// Rank and suit names default to the names of standard deck ranks and suits,
+// ace rank defaults to "A" if deck has it
+type Deck struct {
+	Name      string            `hcl:"name,label"`
+	Ranks     []string          `hcl:"ranks"`
+	Suits     []string          `hcl:"suits"`
+	RankNames map[string]string `hcl:"rank_names,optional"`
+	SuitNames map[string]string `hcl:"suit_names,optional"`
+	Ace       string            `hcl:"ace,optional"`
+	Jokers    uint              `hcl:"jokers,optional"`
+	Copies    uint              `hcl:"copies,optional"`
+	Order     string            `hcl:"order,optional"`
+	// Body keeps block range for diagnostics and attributes
+	// which are not known, they are reported as errors
+	Body hcl.Body `hcl:",remain"`
+}
+
+// RegisterDecks validates configured deck definitions and registers them in deck registry.
+// Returned hcl.Diagnostics points to the invalid deck block or to its unknown attribute
+func (c *Config) RegisterDecks() error {
+	var diags hcl.Diagnostics
+	defs := make([]*deckhelper.DeckDefinition, 0, len(c.Decks))
+	for i := range c.Decks {
+		if unknown := c.Decks[i].unknownAttributes(); unknown.HasErrors() {
+			diags = append(diags, unknown...)
+			continue
+		}
+		def, err := c.Decks[i].Definition()
+		if err == nil {
+			if _, ok := deckhelper.Lookup(def.Name); ok {
+				err = fmt.Errorf("deck definition %q is already registered", def.Name)
+			}
+		}
+		if err != nil {
+			diags = append(diags, c.Decks[i].diagnostic(err))
+			continue
+		}
+		defs = append(defs, def)
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for i, def := range defs {
+		if err := deckhelper.Register(def); err != nil {
+			return hcl.Diagnostics{c.Decks[i].diagnostic(err)}
+		}
+	}
+
+	return nil
+}
+
+// Definition builds and validates deck definition declared by deck block
+func (d *Deck) Definition() (*deckhelper.DeckDefinition, error) {
+	def := &deckhelper.DeckDefinition{
+		Name:      d.Name,
+		Ranks:     d.Ranks,
+		Suits:     d.Suits,
+		RankNames: withDefaults(d.RankNames, d.Ranks, deckhelper.CardsMapping),
+		SuitNames: withDefaults(d.SuitNames, d.Suits, deckhelper.SuitsMapping),
+		Ace:       d.Ace,
+		Copies:    d.Copies,
+		Order:     d.Order,
+		Jokers:    d.Jokers,
+	}
+	if def.Ace == "" {
+		for _, rank := range d.Ranks {
+			if rank == deckhelper.ACE {
+				def.Ace = rank
+			}
+		}
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// unknownAttributes reports each attribute or block of deck block which is not
+// a part of deck definition, e.g. misspelled name of optional attribute
+func (d *Deck) unknownAttributes() hcl.Diagnostics {
+	if d.Body == nil {
+		return nil
+	}
+
+	attrs, diags := d.Body.JustAttributes()
+	unknown := make([]*hcl.Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		unknown = append(unknown, attr)
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].NameRange.Start.Byte < unknown[j].NameRange.Start.Byte
+	})
+	for _, attr := range unknown {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported argument",
+			Detail:   fmt.Sprintf("An argument named %q is not expected in deck %q.", attr.Name, d.Name),
+			Subject:  attr.NameRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+func (d *Deck) diagnostic(err error) *hcl.Diagnostic {
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid deck definition",
+		Detail:   err.Error(),
+	}
+	if d.Body != nil {
+		rng := d.Body.MissingItemRange()
+		if body, ok := d.Body.(*hclsyntax.Body); ok {
+			rng = body.SrcRange
+		}
+		diag.Subject = &rng
+	}
+
+	return diag
+}
+
+// withDefaults returns names completed with default names for given keys
+func withDefaults(names map[string]string, keys []string, defaults map[string]string) map[string]string {
+	res := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if name, ok := defaults[key]; ok {
+			res[key] = name
+		}
+	}
+	for key, name := range names {
+		res[key] = name
+	}
+
+	return res
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/stretchr/testify/require"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+)
+
+const testConfigHead = `
+db {
+  database = "test"
+  user = "test"
+  password = "test"
+  host = "localhost"
+  port = 5437
+  sslmode = "disable"
+  migrations = "./db/migrations"
+}
+
+app {
+  listening = 8083
+  prod = true
+  disableStacktrace = true
+}
+`
+
+func decodeTestConfig(t *testing.T, decks string) *Config {
+	var cfg Config
+	err := hclsimple.Decode("test.hcl", []byte(testConfigHead+decks), nil, &cfg)
+	require.NoError(t, err)
+
+	return &cfg
+}
+
+func TestConfig_RegisterDecks(t *testing.T) {
+	cfg := decodeTestConfig(t, `
+deck "config-short" {
+  ranks = ["A", "K"]
+  suits = ["S", "H"]
+  order = "ranks"
+  jokers = 1
+}
+
+deck "config-named" {
+  ranks = ["1", "2"]
+  suits = ["R"]
+  rank_names = { "1" = "ONE", "2" = "TWO" }
+  suit_names = { "R" = "RED" }
+  ace = "1"
+}
+`)
+	require.NoError(t, cfg.RegisterDecks())
+
+	def, ok := deckhelper.Lookup("config-short")
+	require.True(t, ok)
+	require.Equal(t, []string{"AS", "AH", "KS", "KH"}, def.Codes())
+	require.Equal(t, uint(1), def.Jokers)
+	require.Equal(t, "ACE", def.Cards()["AS"].Value)
+	require.Equal(t, "HEARTS", def.Cards()["KH"].Suit)
+	require.Equal(t, deckhelper.ACE, def.Ace)
+
+	def, ok = deckhelper.Lookup("config-named")
+	require.True(t, ok)
+	require.Equal(t, deckhelper.CardDefinition{Code: "2R", Value: "TWO", Suit: "RED"}, def.Cards()["2R"])
+	require.Equal(t, "1", def.Ace)
+
+	// already registered
+	err := cfg.RegisterDecks()
+	require.Error(t, err)
+	diags, ok := err.(hcl.Diagnostics)
+	require.True(t, ok)
+	require.Len(t, diags, 2)
+}
+
+func TestConfig_RegisterDecks_invalid(t *testing.T) {
+	cfg := decodeTestConfig(t, `
+deck "config-invalid" {
+  ranks = ["1", "2"]
+  suits = ["R"]
+}
+`)
+	err := cfg.RegisterDecks()
+	require.Error(t, err)
+
+	diags, ok := err.(hcl.Diagnostics)
+	require.True(t, ok)
+	require.Len(t, diags, 1)
+	require.Equal(t, "Invalid deck definition", diags[0].Summary)
+	require.Equal(t, `deck definition "config-invalid" has rank "1" without name`, diags[0].Detail)
+	require.Equal(t, "test.hcl", diags[0].Subject.Filename)
+	require.Equal(t, 18, diags[0].Subject.Start.Line)
+
+	_, ok = deckhelper.Lookup("config-invalid")
+	require.False(t, ok)
+}
+
+func TestConfig_RegisterDecks_unknownAttributes(t *testing.T) {
+	cfg := decodeTestConfig(t, `
+deck "config-misspelled" {
+  ranks = ["A", "K"]
+  suits = ["S", "H"]
+  ordr = "bogus"
+  jokres = 2
+}
+`)
+	err := cfg.RegisterDecks()
+	require.Error(t, err)
+
+	diags, ok := err.(hcl.Diagnostics)
+	require.True(t, ok)
+	require.Len(t, diags, 2)
+	require.Equal(t, "Unsupported argument", diags[0].Summary)
+	require.Equal(t, `An argument named "ordr" is not expected in deck "config-misspelled".`, diags[0].Detail)
+	require.Equal(t, 21, diags[0].Subject.Start.Line)
+	require.Equal(t, `An argument named "jokres" is not expected in deck "config-misspelled".`, diags[1].Detail)
+	require.Equal(t, 22, diags[1].Subject.Start.Line)
+
+	_, ok = deckhelper.Lookup("config-misspelled")
+	require.False(t, ok)
+}
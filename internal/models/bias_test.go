@@ -0,0 +1,47 @@
+//go:build stats
+// +build stats
+
+package models
+
+import (
+	"testing"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/card-deck/pkg/deck/stats"
+	"github.com/stretchr/testify/require"
+)
+
+// drawAllShuffler orders deck by drawing all its cards in random mode
+func drawAllShuffler(draw func(codes []string, rnd deckhelper.Random) ([]string, error)) deckhelper.Shuffler {
+	return deckhelper.ShufflerFunc(func(codes []string, rnd deckhelper.Random) {
+		drawn, err := draw(codes, rnd)
+		if err != nil {
+			panic(err)
+		}
+		copy(codes, drawn)
+	})
+}
+
+func TestRandomDrawBias(t *testing.T) {
+	shufflers := map[string]deckhelper.Shuffler{
+		"DrawRandomNCars": drawAllShuffler(func(codes []string, _ deckhelper.Random) ([]string, error) {
+			return DrawRandomNCars(uint(len(codes)), codes)
+		}),
+		"DrawCodes": drawAllShuffler(func(codes []string, rnd deckhelper.Random) ([]string, error) {
+			drawn, _, err := DrawCodes(DrawModeRandom, uint(len(codes)), nil, codes, rnd)
+			return drawn, err
+		}),
+	}
+
+	for name, shuffler := range shufflers {
+		t.Run(name, func(t *testing.T) {
+			report := stats.Run(shuffler, deckhelper.NewRandom(), 52, 50000)
+			t.Logf("chi-square z %.2f, rising sequences %.2f, adjacent pairs %.3f",
+				report.ChiSquareZScore(), report.MeanRisingSequences, report.MeanAdjacentPairs)
+
+			require.Less(t, report.ChiSquareZScore(), 5.0)
+			require.InDelta(t, report.ExpectedRisingSequences(), report.MeanRisingSequences, 0.1)
+			require.InDelta(t, report.ExpectedAdjacentPairs(), report.MeanAdjacentPairs, 0.05)
+		})
+	}
+}
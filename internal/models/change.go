@@ -0,0 +1,96 @@
+package models
+
+import "fmt"
+
+// Change is a type that represents change of ordered codes of deck or pile,
+// it is either removal of codes at given positions, insertion of codes
+// at given positions or a whole new order of codes
+type Change struct {
+	// Removed are ascending positions of codes removed from previous codes
+	Removed []uint `json:"removed,omitempty"`
+	// Inserted are ascending positions of Codes in new codes
+	Inserted []uint `json:"inserted,omitempty"`
+	// Codes are inserted codes
+	Codes []string `json:"codes,omitempty"`
+	// Order replaces previous codes if set
+	Order []string `json:"order,omitempty"`
+}
+
+// DiffCodes returns change which turns codes before into codes after.
+// Removal is used if after keeps the order of the rest of before, insertion is used
+// if before keeps its order inside of after, otherwise the whole new order is stored
+func DiffCodes(before, after []string) Change {
+	if removed, ok := subsequenceGaps(after, before); ok {
+		return Change{Removed: removed}
+	}
+	if inserted, ok := subsequenceGaps(before, after); ok {
+		codes := make([]string, 0, len(inserted))
+		for _, pos := range inserted {
+			codes = append(codes, after[pos])
+		}
+		return Change{Inserted: inserted, Codes: codes}
+	}
+	return Change{Order: append([]string{}, after...)}
+}
+
+// Apply returns codes changed by change, given codes are not modified
+func (c *Change) Apply(codes []string) ([]string, error) {
+	switch {
+	case c.Order != nil:
+		return append([]string{}, c.Order...), nil
+	case len(c.Removed) > 0:
+		for i, pos := range c.Removed {
+			if int(pos) >= len(codes) || i > 0 && pos <= c.Removed[i-1] {
+				return nil, fmt.Errorf("invalid removed position %d", pos)
+			}
+		}
+		return RemovePositions(c.Removed, codes), nil
+	case len(c.Inserted) > 0:
+		if len(c.Inserted) != len(c.Codes) {
+			return nil, fmt.Errorf("inserted positions don't match codes")
+		}
+		size := len(codes) + len(c.Inserted)
+		result := make([]string, 0, size)
+		next, kept := 0, 0
+		for pos := 0; pos < size; pos++ {
+			if next < len(c.Inserted) && int(c.Inserted[next]) == pos {
+				result = append(result, c.Codes[next])
+				next++
+				continue
+			}
+			if kept >= len(codes) {
+				return nil, fmt.Errorf("invalid inserted position %d", c.Inserted[next])
+			}
+			result = append(result, codes[kept])
+			kept++
+		}
+		if next != len(c.Inserted) {
+			return nil, fmt.Errorf("invalid inserted position %d", c.Inserted[next])
+		}
+		return result, nil
+	default:
+		return append([]string{}, codes...), nil
+	}
+}
+
+// subsequenceGaps checks if sub is a subsequence of codes and returns
+// ascending positions of codes which are not matched by sub
+func subsequenceGaps(sub, codes []string) ([]uint, bool) {
+	if len(sub) > len(codes) {
+		return nil, false
+	}
+
+	gaps := make([]uint, 0, len(codes)-len(sub))
+	matched := 0
+	for pos, code := range codes {
+		if matched < len(sub) && sub[matched] == code {
+			matched++
+			continue
+		}
+		gaps = append(gaps, uint(pos))
+	}
+	if matched != len(sub) {
+		return nil, false
+	}
+	return gaps, true
+}
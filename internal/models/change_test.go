@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCodes(t *testing.T) {
+	cases := []struct {
+		name     string
+		before   []string
+		after    []string
+		expected Change
+	}{
+		{
+			name:     "removed",
+			before:   []string{"AS", "KD", "2C", "3H"},
+			after:    []string{"KD", "3H"},
+			expected: Change{Removed: []uint{0, 2}},
+		},
+		{
+			name:     "inserted",
+			before:   []string{"KD", "3H"},
+			after:    []string{"AS", "KD", "2C", "3H"},
+			expected: Change{Inserted: []uint{0, 2}, Codes: []string{"AS", "2C"}},
+		},
+		{
+			name:     "reordered",
+			before:   []string{"AS", "KD", "2C"},
+			after:    []string{"2C", "AS", "KD"},
+			expected: Change{Order: []string{"2C", "AS", "KD"}},
+		},
+		{
+			name:     "unchanged",
+			before:   []string{"AS", "KD"},
+			after:    []string{"AS", "KD"},
+			expected: Change{Removed: []uint{}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			change := DiffCodes(c.before, c.after)
+			require.Equal(t, c.expected, change)
+
+			codes, err := change.Apply(c.before)
+			require.NoError(t, err)
+			require.Equal(t, c.after, codes)
+		})
+	}
+}
+
+func TestChange_Apply(t *testing.T) {
+	codes := []string{"AS", "KD"}
+
+	_, err := (&Change{Removed: []uint{2}}).Apply(codes)
+	require.EqualError(t, err, "invalid removed position 2")
+	_, err = (&Change{Removed: []uint{1, 0}}).Apply(codes)
+	require.EqualError(t, err, "invalid removed position 0")
+	_, err = (&Change{Inserted: []uint{0}}).Apply(codes)
+	require.EqualError(t, err, "inserted positions don't match codes")
+	_, err = (&Change{Inserted: []uint{4}, Codes: []string{"2C"}}).Apply(codes)
+	require.EqualError(t, err, "invalid inserted position 4")
+
+	changed, err := (&Change{}).Apply(codes)
+	require.NoError(t, err)
+	require.Equal(t, codes, changed)
+}
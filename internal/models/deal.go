@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Deal is a type that represents
+	// cards dealt to players from deck
+	Deal struct {
+		DeckID    string  `json:"deck_id"`
+		Remaining uint    `json:"remaining"`
+		Hands     []*Hand `json:"hands"`
+	}
+
+	// Hand is a type that represents cards dealt to player
+	// in order of dealing, they are put on top of player's pile
+	Hand struct {
+		Player    string   `json:"player"`
+		Cards     Cards    `json:"cards"`
+		CardCodes []string `json:"-"`
+	}
+)
+
+// PlayerNames returns names of given count of players: player1, player2, etc.
+func PlayerNames(count uint) []string {
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprintf("player%d", i+1)
+	}
+	return names
+}
+
+// ValidatePlayerNames checks that names of players are not empty and unique
+func ValidatePlayerNames(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("player name cannot be empty")
+		}
+		if seen[name] {
+			return fmt.Errorf("player %q is given more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// DealRoundRobin deals cards from the top of the deck one at a time to each player in turn,
+// returns codes dealt to each player in order of dealing along with codes left in deck
+func DealRoundRobin(players, cardsEach uint, codes []string) ([][]string, []string, error) {
+	need := players * cardsEach
+	if uint(len(codes)) < need {
+		return nil, nil, fmt.Errorf("deck remaining %d cards, %d needed", len(codes), need)
+	}
+
+	hands := make([][]string, players)
+	for i := range hands {
+		hands[i] = make([]string, 0, cardsEach)
+	}
+	for i, code := range codes[:need] {
+		player := uint(i) % players
+		hands[player] = append(hands[player], code)
+	}
+
+	return hands, append([]string{}, codes[need:]...), nil
+}
+
+// PutOnTop returns pile codes after given codes are put on top of it one by one,
+// so the last put code becomes the top one
+func PutOnTop(codes, pile []string) []string {
+	result := make([]string, 0, len(codes)+len(pile))
+	for i := len(codes) - 1; i >= 0; i-- {
+		result = append(result, codes[i])
+	}
+	return append(result, pile...)
+}
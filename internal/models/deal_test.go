@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealRoundRobin(t *testing.T) {
+	codes := []string{"AS", "2S", "3S", "4S", "5S", "6S", "7S"}
+
+	hands, left, err := DealRoundRobin(3, 2, codes)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"AS", "4S"}, {"2S", "5S"}, {"3S", "6S"}}, hands)
+	require.Equal(t, []string{"7S"}, left)
+	// given codes are not changed
+	require.Equal(t, "AS", codes[0])
+
+	hands, left, err = DealRoundRobin(1, 7, codes)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{codes}, hands)
+	require.Empty(t, left)
+
+	_, _, err = DealRoundRobin(4, 2, codes)
+	require.EqualError(t, err, "deck remaining 7 cards, 8 needed")
+}
+
+func TestPlayerNames(t *testing.T) {
+	require.Equal(t, []string{"player1", "player2"}, PlayerNames(2))
+
+	require.NoError(t, ValidatePlayerNames([]string{"north", "south"}))
+	require.EqualError(t, ValidatePlayerNames([]string{"north", " "}), "player name cannot be empty")
+	require.EqualError(t, ValidatePlayerNames([]string{"north", "north"}), `player "north" is given more than once`)
+}
+
+func TestPutOnTop(t *testing.T) {
+	require.Equal(t, []string{"3S", "2S", "AS", "KD"}, PutOnTop([]string{"AS", "2S", "3S"}, []string{"KD"}))
+	require.Empty(t, PutOnTop(nil, nil))
+}
@@ -2,7 +2,6 @@ package models
 
 import (
 	"fmt"
-	"math/rand"
 	"time"
 
 	deckhelper "github.com/card-deck/pkg/deck"
@@ -14,90 +13,411 @@ type (
 	// Deck is a type that represents
 	// the model of the decks table.
 	Deck struct {
-		DeckID     string         `json:"deck_id" db:"deck_id"`
-		IsShuffled bool           `json:"is_shuffled" db:"is_shuffled"`
-		Remaining  uint           `json:"remaining" db:"remaining"`
-		Cards      Cards          `json:"cards,omitempty"`
-		CardCodes  pq.StringArray `json:"-" db:"card_codes"`
-		CreatedAt  time.Time      `json:"created_at" db:"created_at"`
-		UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+		DeckID        string         `json:"deck_id" db:"deck_id"`
+		Type          string         `json:"type" db:"deck_type"`
+		IsShuffled    bool           `json:"is_shuffled" db:"is_shuffled"`
+		DecksCount    uint           `json:"decks_count" db:"decks_count"`
+		TemplateID    *string        `json:"template_id,omitempty" db:"template_id"`
+		Remaining     uint           `json:"remaining" db:"remaining"`
+		Cards         Cards          `json:"cards,omitempty"`
+		Drawn         Cards          `json:"drawn,omitempty"`
+		CardCodes     pq.StringArray `json:"-" db:"card_codes"`
+		OriginalCodes pq.StringArray `json:"-" db:"original_codes"`
+		RNG           string         `json:"rng" db:"rng"`
+		Seed          *int64         `json:"seed,omitempty" db:"seed"`
+		RandomStep    uint           `json:"-" db:"random_step"`
+		Commitment    *string        `json:"commitment,omitempty" db:"commitment"`
+		ClientSeed    *string        `json:"client_seed,omitempty" db:"client_seed"`
+		ServerSeed    *string        `json:"-" db:"server_seed"`
+		IsClosed      bool           `json:"is_closed" db:"is_closed"`
+		IsCompetitive bool           `json:"is_competitive" db:"is_competitive"`
+		NoPeek        bool           `json:"no_peek" db:"no_peek"`
+		Version       uint           `json:"version" db:"version"`
+		CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+		UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
 	}
 
 	// Card is a type that represents card object
 	Card struct {
-		Value string `json:"value"`
-		Suit  string `json:"suit"`
-		Code  string `json:"code"`
+		Value      string                 `json:"value"`
+		Suit       string                 `json:"suit,omitempty"`
+		Code       string                 `json:"code"`
+		Attributes map[string]interface{} `json:"attributes,omitempty"`
 	}
 
 	// Cards is a type that represents
 	// list of cards
 	Cards []*Card
+
+	// ReturnMode is a type that represents
+	// the way cards are put back into deck
+	ReturnMode string
+
+	// DrawMode is a type that represents
+	// the way cards are drawn from deck
+	DrawMode string
+
+	// SortOrder is a type that represents
+	// order of ranks used on sorting cards
+	SortOrder string
 )
 
-// BuildCardsFromCodes build card objects from card codes
-func BuildCardsFromCodes(codes []string) (Cards, error) {
-	if !deckhelper.IsValidCodes(codes) {
-		return nil, fmt.Errorf("codes is not valid")
+// Possible draw modes, top of the deck is the first code
+const (
+	DrawModeTop      DrawMode = "top"
+	DrawModeBottom   DrawMode = "bottom"
+	DrawModeRandom   DrawMode = "random"
+	DrawModePosition DrawMode = "position"
+	DrawModeCode     DrawMode = "code"
+)
+
+// Possible return modes
+const (
+	ReturnModeTop    ReturnMode = "top"
+	ReturnModeBottom ReturnMode = "bottom"
+	ReturnModeRandom ReturnMode = "random"
+)
+
+// Possible sort orders
+const (
+	SortOrderAceHigh SortOrder = "ace_high"
+	SortOrderAceLow  SortOrder = "ace_low"
+)
+
+// IsValid checks if return mode is known, empty mode is treated as top
+func (m ReturnMode) IsValid() bool {
+	switch m {
+	case "", ReturnModeTop, ReturnModeBottom, ReturnModeRandom:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if sort order is known, empty order is treated as ace high
+func (o SortOrder) IsValid() bool {
+	switch o {
+	case "", SortOrderAceHigh, SortOrderAceLow:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if draw mode is known, empty mode is treated as top
+func (m DrawMode) IsValid() bool {
+	switch m {
+	case "", DrawModeTop, DrawModeBottom, DrawModeRandom, DrawModePosition, DrawModeCode:
+		return true
 	}
+	return false
+}
+
+// BuildCardsFromCodes build card objects from standard deck card codes
+func BuildCardsFromCodes(codes []string) (Cards, error) {
+	return BuildDeckCards(deckhelper.Standard, codes)
+}
+
+// BuildDeckCards build card objects from card codes of given deck definition
+func BuildDeckCards(def *deckhelper.DeckDefinition, codes []string) (Cards, error) {
 	if len(codes) == 0 {
 		return Cards{}, nil
 	}
+
+	known := def.Cards()
 	cards := make(Cards, 0, len(codes))
 	for _, code := range codes {
-		var (
-			cardCode  string
-			suiteCode string
-		)
-
-		// to get for example 10S card
-		// TODO: refactor to make more understandable??
-		if len(code) == 3 {
-			cardCode = code[0:2]
-			suiteCode = code[2:3]
-		} else {
-			cardCode = code[0:1]
-			suiteCode = code[1:2]
-		}
-
-		card := &Card{
-			Value: deckhelper.CardsMapping[cardCode],
-			Suit:  deckhelper.SuitsMapping[suiteCode],
-			Code:  code,
+		card, ok := known[code]
+		if !ok {
+			return nil, fmt.Errorf("codes is not valid")
 		}
 
-		cards = append(cards, card)
+		cards = append(cards, &Card{
+			Value:      card.Value,
+			Suit:       card.Suit,
+			Code:       code,
+			Attributes: card.Attributes,
+		})
 	}
 	return cards, nil
 }
 
+// Definition returns definition of deck type
+func (d *Deck) Definition() (*deckhelper.DeckDefinition, error) {
+	def, ok := deckhelper.Lookup(d.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown deck type %q", d.Type)
+	}
+	return def, nil
+}
+
+// Random returns random generator for the next random operation on deck.
+// Seeded deck gives reproducible sequence, its random step is advanced on each call
+func (d *Deck) Random() deckhelper.Random {
+	if d.RNG == deckhelper.RNGCrypto {
+		return deckhelper.NewCryptoRandom()
+	}
+	if d.Seed == nil {
+		return deckhelper.NewRandom()
+	}
+	rnd := deckhelper.NewSeededRandom(*d.Seed, d.RandomStep)
+	d.RandomStep++
+	return rnd
+}
+
+// Reseed starts new random sequence of deck from given seed
+func (d *Deck) Reseed(seed int64) {
+	d.Seed = &seed
+	d.RandomStep = 0
+}
+
 // DrawRandomNCars returns N random codes from slice
 func DrawRandomNCars(count uint, codes []string) ([]string, error) {
-	if int(count) > len(codes) {
-		return nil, fmt.Errorf("count cannot be greater then length of codes slice")
+	positions, err := randomPositions(count, len(codes), deckhelper.NewRandom())
+	if err != nil {
+		return nil, err
 	}
+	return pickCodes(positions, codes), nil
+}
 
-	rand.Seed(time.Now().UnixNano())
-	randomize := rand.Perm(len(codes))
-	var result []string
-	for _, v := range randomize[:count] {
-		result = append(result, codes[v])
+// DrawTopNCards returns N codes from the top of the deck
+func DrawTopNCards(count uint, codes []string) ([]string, error) {
+	positions, err := topPositions(count, len(codes))
+	if err != nil {
+		return nil, err
 	}
+	return pickCodes(positions, codes), nil
+}
 
-	return result, nil
+// DrawBottomNCards returns N codes from the bottom of the deck,
+// the bottom card goes first
+func DrawBottomNCards(count uint, codes []string) ([]string, error) {
+	positions, err := bottomPositions(count, len(codes))
+	if err != nil {
+		return nil, err
+	}
+	return pickCodes(positions, codes), nil
+}
+
+// DrawCardsAtPositions returns codes placed at given zero-based
+// positions counting from the top of the deck
+func DrawCardsAtPositions(positions []uint, codes []string) ([]string, error) {
+	if err := validatePositions(positions, len(codes)); err != nil {
+		return nil, err
+	}
+	return pickCodes(positions, codes), nil
+}
+
+// DrawCardsByCodes draws given codes from deck and returns them along with codes left in deck.
+// Each drawn code takes one copy closest to the top of the deck,
+// if deck holds less copies than requested, list of missed codes is returned
+func DrawCardsByCodes(drawCodes, codes []string) ([]string, []string, []string) {
+	if missing := ExcessCodes(drawCodes, codes); len(missing) > 0 {
+		return nil, nil, missing
+	}
+
+	taken := make([]bool, len(codes))
+	positions := make([]uint, 0, len(drawCodes))
+	for _, code := range drawCodes {
+		for i, c := range codes {
+			if c == code && !taken[i] {
+				taken[i] = true
+				positions = append(positions, uint(i))
+				break
+			}
+		}
+	}
+
+	return pickCodes(positions, codes), RemovePositions(positions, codes), nil
 }
 
-// RemoveDrawnCodes removes drawn codes from slice keeping order
+// DrawCodes draws codes from deck according to given mode and returns
+// drawn codes along with codes left in deck keeping their order.
+// Count is ignored for position mode, code mode is handled by DrawCardsByCodes,
+// random generator is used by random mode only
+func DrawCodes(mode DrawMode, count uint, positions []uint, codes []string, rnd deckhelper.Random) ([]string, []string, error) {
+	var err error
+	switch mode {
+	case "", DrawModeTop:
+		positions, err = topPositions(count, len(codes))
+	case DrawModeBottom:
+		positions, err = bottomPositions(count, len(codes))
+	case DrawModeRandom:
+		positions, err = randomPositions(count, len(codes), rnd)
+	case DrawModePosition:
+		err = validatePositions(positions, len(codes))
+	default:
+		err = fmt.Errorf("unknown draw mode %q", mode)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pickCodes(positions, codes), RemovePositions(positions, codes), nil
+}
+
+// RemovePositions returns codes without ones placed at given positions keeping order
+func RemovePositions(positions []uint, codes []string) []string {
+	removed := make(map[uint]bool, len(positions))
+	for _, pos := range positions {
+		removed[pos] = true
+	}
+
+	result := make([]string, 0, len(codes))
+	for i, code := range codes {
+		if !removed[uint(i)] {
+			result = append(result, code)
+		}
+	}
+
+	return result
+}
+
+// RemoveDrawnCodes removes drawn codes from slice keeping order,
+// one copy closest to the top is removed for each drawn code
 func RemoveDrawnCodes(drawnCodes, allCodes []string) []string {
+	drawn := countCodes(drawnCodes)
 	for i := 0; i < len(allCodes); i++ {
 		code := allCodes[i]
-		for _, rem := range drawnCodes {
-			if code == rem {
-				allCodes = append(allCodes[:i], allCodes[i+1:]...)
-				i--
-				break
-			}
+		if drawn[code] > 0 {
+			drawn[code]--
+			allCodes = append(allCodes[:i], allCodes[i+1:]...)
+			i--
 		}
 	}
 	return allCodes
 }
+
+func topPositions(count uint, size int) ([]uint, error) {
+	if int(count) > size {
+		return nil, fmt.Errorf("count cannot be greater then length of codes slice")
+	}
+
+	positions := make([]uint, 0, count)
+	for i := uint(0); i < count; i++ {
+		positions = append(positions, i)
+	}
+	return positions, nil
+}
+
+func bottomPositions(count uint, size int) ([]uint, error) {
+	if int(count) > size {
+		return nil, fmt.Errorf("count cannot be greater then length of codes slice")
+	}
+
+	positions := make([]uint, 0, count)
+	for i := size - 1; i >= size-int(count); i-- {
+		positions = append(positions, uint(i))
+	}
+	return positions, nil
+}
+
+func randomPositions(count uint, size int, rnd deckhelper.Random) ([]uint, error) {
+	if int(count) > size {
+		return nil, fmt.Errorf("count cannot be greater then length of codes slice")
+	}
+
+	randomize := deckhelper.Perm(size, rnd)
+	positions := make([]uint, 0, count)
+	for _, v := range randomize[:count] {
+		positions = append(positions, uint(v))
+	}
+	return positions, nil
+}
+
+func validatePositions(positions []uint, size int) error {
+	if len(positions) == 0 {
+		return fmt.Errorf("positions cannot be empty")
+	}
+
+	seen := make(map[uint]bool, len(positions))
+	for _, pos := range positions {
+		if int(pos) >= size {
+			return fmt.Errorf("position %d is out of deck", pos)
+		}
+		if seen[pos] {
+			return fmt.Errorf("position %d is duplicated", pos)
+		}
+		seen[pos] = true
+	}
+	return nil
+}
+
+func pickCodes(positions []uint, codes []string) []string {
+	result := make([]string, 0, len(positions))
+	for _, pos := range positions {
+		result = append(result, codes[pos])
+	}
+	return result
+}
+
+// ReturnCodes puts codes back into deck according to given mode
+// and returns new deck codes, random generator is used by random mode only
+func ReturnCodes(mode ReturnMode, returned, codes []string, rnd deckhelper.Random) ([]string, error) {
+	result := make([]string, 0, len(codes)+len(returned))
+	switch mode {
+	case "", ReturnModeTop:
+		result = append(result, returned...)
+		result = append(result, codes...)
+	case ReturnModeBottom:
+		result = append(result, codes...)
+		result = append(result, returned...)
+	case ReturnModeRandom:
+		result = append(result, codes...)
+		for _, code := range returned {
+			pos := rnd.Intn(len(result) + 1)
+			result = append(result, "")
+			copy(result[pos+1:], result[pos:])
+			result[pos] = code
+		}
+	default:
+		return nil, fmt.Errorf("unknown return mode %q", mode)
+	}
+
+	return result, nil
+}
+
+// MissingCodes returns codes which are not presented in given list keeping order
+func MissingCodes(codes, in []string) []string {
+	presented := make(map[string]bool, len(in))
+	for _, code := range in {
+		presented[code] = true
+	}
+
+	var missing []string
+	for _, code := range codes {
+		if !presented[code] {
+			missing = append(missing, code)
+		}
+	}
+
+	return missing
+}
+
+// ExcessCodes returns codes which have more copies than given list holds keeping order
+func ExcessCodes(codes, in []string) []string {
+	available := countCodes(in)
+
+	var excess []string
+	for _, code := range codes {
+		if available[code] == 0 {
+			excess = append(excess, code)
+			continue
+		}
+		available[code]--
+	}
+
+	return excess
+}
+
+// SubtractCodes returns codes of the first list without
+// one copy for each code of the second list keeping order
+func SubtractCodes(codes, subtracted []string) []string {
+	return RemoveDrawnCodes(subtracted, append([]string(nil), codes...))
+}
+
+func countCodes(codes []string) map[string]int {
+	counts := make(map[string]int, len(codes))
+	for _, code := range codes {
+		counts[code]++
+	}
+	return counts
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 
+	deckhelper "github.com/card-deck/pkg/deck"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,6 +47,71 @@ func TestBuildCardsFromCodes(t *testing.T) {
 	cards, err = BuildCardsFromCodes([]string{"AS", "KD", "2C", "10C"})
 	require.NoError(t, err)
 	require.EqualValues(t, expectedCards, cards)
+
+	cards, err = BuildCardsFromCodes([]string{"X2", "10C", "X1"})
+	require.NoError(t, err)
+	require.EqualValues(t, []*Card{
+		{Value: "JOKER", Code: "X2"},
+		{Value: "10", Suit: "CLUBS", Code: "10C"},
+		{Value: "JOKER", Code: "X1"},
+	}, cards)
+
+	cards, err = BuildCardsFromCodes([]string{"AS", "AS"})
+	require.NoError(t, err)
+	require.Len(t, cards, 2)
+}
+
+func TestBuildDeckCards(t *testing.T) {
+	cards, err := BuildDeckCards(deckhelper.Spanish40, []string{"1O", "12B", "X1"})
+	require.NoError(t, err)
+	require.EqualValues(t, []*Card{
+		{Value: "AS", Suit: "OROS", Code: "1O"},
+		{Value: "REY", Suit: "BASTOS", Code: "12B"},
+		{Value: "JOKER", Code: "X1"},
+	}, cards)
+
+	_, err = BuildDeckCards(deckhelper.Spanish40, []string{"AS"})
+	require.EqualError(t, err, "codes is not valid")
+}
+
+func TestDeck_Definition(t *testing.T) {
+	def, err := (&Deck{}).Definition()
+	require.NoError(t, err)
+	require.Equal(t, deckhelper.Standard, def)
+
+	def, err = (&Deck{Type: deckhelper.TarotDeckType}).Definition()
+	require.NoError(t, err)
+	require.Equal(t, deckhelper.Tarot, def)
+
+	_, err = (&Deck{Type: "unknown"}).Definition()
+	require.EqualError(t, err, `unknown deck type "unknown"`)
+}
+
+func TestDeck_Random(t *testing.T) {
+	var seed int64 = 42
+	replay := func() []string {
+		deck := &Deck{Seed: &seed}
+		codes := deckhelper.CreateDefaultCodes()
+		deckhelper.Shuffle(codes, deck.Random())
+		drawn, _, err := DrawCodes(DrawModeRandom, 5, nil, codes, deck.Random())
+		require.NoError(t, err)
+		require.Equal(t, uint(2), deck.RandomStep)
+		return append(codes[:5:5], drawn...)
+	}
+	require.Equal(t, replay(), replay())
+
+	deck := &Deck{}
+	deck.Reseed(7)
+	require.Equal(t, int64(7), *deck.Seed)
+	require.Equal(t, uint(0), deck.RandomStep)
+
+	unseeded := &Deck{}
+	require.NotNil(t, unseeded.Random())
+	require.Equal(t, uint(0), unseeded.RandomStep)
+
+	crypto := &Deck{RNG: deckhelper.RNGCrypto}
+	require.Equal(t, deckhelper.NewCryptoRandom(), crypto.Random())
+	require.Equal(t, uint(0), crypto.RandomStep)
 }
 
 func TestDrawRandomNCars(t *testing.T) {
@@ -92,4 +158,183 @@ func TestRemoveDrawnCodes(t *testing.T) {
 	result = RemoveDrawnCodes([]string{"AS"}, []string{})
 	require.Len(t, result, 0)
 	require.Equal(t, []string{}, result)
+	result = RemoveDrawnCodes([]string{"AS"}, []string{"AS", "KD", "AS"})
+	require.Equal(t, []string{"KD", "AS"}, result)
+
+	result = RemoveDrawnCodes([]string{"AS", "AS"}, []string{"AS", "KD", "AS"})
+	require.Equal(t, []string{"KD"}, result)
+}
+
+func TestDrawTopNCards(t *testing.T) {
+	result, err := DrawTopNCards(2, []string{"AS", "KD", "2C"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"AS", "KD"}, result)
+
+	_, err = DrawTopNCards(4, []string{"AS", "KD", "2C"})
+	require.EqualError(t, err, "count cannot be greater then length of codes slice")
+}
+
+func TestDrawBottomNCards(t *testing.T) {
+	result, err := DrawBottomNCards(2, []string{"AS", "KD", "2C"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2C", "KD"}, result)
+
+	result, err = DrawBottomNCards(3, []string{"AS", "KD", "2C"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2C", "KD", "AS"}, result)
+
+	_, err = DrawBottomNCards(4, []string{"AS", "KD", "2C"})
+	require.EqualError(t, err, "count cannot be greater then length of codes slice")
+}
+
+func TestDrawCardsAtPositions(t *testing.T) {
+	result, err := DrawCardsAtPositions([]uint{2, 0}, []string{"AS", "KD", "2C"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2C", "AS"}, result)
+
+	_, err = DrawCardsAtPositions([]uint{3}, []string{"AS", "KD", "2C"})
+	require.EqualError(t, err, "position 3 is out of deck")
+
+	_, err = DrawCardsAtPositions([]uint{1, 1}, []string{"AS", "KD", "2C"})
+	require.EqualError(t, err, "position 1 is duplicated")
+
+	_, err = DrawCardsAtPositions(nil, []string{"AS", "KD", "2C"})
+	require.EqualError(t, err, "positions cannot be empty")
+}
+
+func TestDrawCodes(t *testing.T) {
+	codes := []string{"AS", "KD", "2C", "10C"}
+
+	drawn, left, err := DrawCodes("", 2, nil, codes, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AS", "KD"}, drawn)
+	require.Equal(t, []string{"2C", "10C"}, left)
+
+	drawn, left, err = DrawCodes(DrawModeTop, 1, nil, codes, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AS"}, drawn)
+	require.Equal(t, []string{"KD", "2C", "10C"}, left)
+
+	drawn, left, err = DrawCodes(DrawModeBottom, 1, nil, codes, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10C"}, drawn)
+	require.Equal(t, []string{"AS", "KD", "2C"}, left)
+
+	drawn, left, err = DrawCodes(DrawModeRandom, 4, nil, codes, deckhelper.NewSeededRandom(1, 0))
+	require.NoError(t, err)
+	require.ElementsMatch(t, codes, drawn)
+	require.Empty(t, left)
+
+	drawn, left, err = DrawCodes(DrawModePosition, 0, []uint{1, 3}, codes, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"KD", "10C"}, drawn)
+	require.Equal(t, []string{"AS", "2C"}, left)
+
+	_, _, err = DrawCodes("middle", 1, nil, codes, nil)
+	require.EqualError(t, err, `unknown draw mode "middle"`)
+
+	require.Equal(t, []string{"AS", "KD", "2C", "10C"}, codes)
+
+	// the exact drawn copy is removed from multi-deck shoe
+	drawn, left, err = DrawCodes(DrawModeBottom, 1, nil, []string{"AS", "KD", "AS"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AS"}, drawn)
+	require.Equal(t, []string{"AS", "KD"}, left)
+}
+
+func TestDrawMode_IsValid(t *testing.T) {
+	require.True(t, DrawMode("").IsValid())
+	require.True(t, DrawModeTop.IsValid())
+	require.True(t, DrawModeBottom.IsValid())
+	require.True(t, DrawModeRandom.IsValid())
+	require.True(t, DrawModePosition.IsValid())
+	require.False(t, DrawMode("middle").IsValid())
+}
+
+func TestSortOrder_IsValid(t *testing.T) {
+	require.True(t, SortOrder("").IsValid())
+	require.True(t, SortOrderAceHigh.IsValid())
+	require.True(t, SortOrderAceLow.IsValid())
+	require.False(t, SortOrder("king_high").IsValid())
+}
+
+func TestDrawCardsByCodes(t *testing.T) {
+	codes := []string{"AS", "KD", "2C", "10H"}
+
+	drawn, left, missing := DrawCardsByCodes([]string{"10H", "AS"}, codes)
+	require.Empty(t, missing)
+	require.Equal(t, []string{"10H", "AS"}, drawn)
+	require.Equal(t, []string{"KD", "2C"}, left)
+
+	drawn, left, missing = DrawCardsByCodes([]string{"AS", "QH", "3D"}, codes)
+	require.Nil(t, drawn)
+	require.Nil(t, left)
+	require.Equal(t, []string{"QH", "3D"}, missing)
+
+	shoe := []string{"AS", "KD", "AS", "2C"}
+
+	drawn, left, missing = DrawCardsByCodes([]string{"AS"}, shoe)
+	require.Empty(t, missing)
+	require.Equal(t, []string{"AS"}, drawn)
+	require.Equal(t, []string{"KD", "AS", "2C"}, left)
+
+	drawn, left, missing = DrawCardsByCodes([]string{"AS", "AS"}, shoe)
+	require.Empty(t, missing)
+	require.Equal(t, []string{"AS", "AS"}, drawn)
+	require.Equal(t, []string{"KD", "2C"}, left)
+
+	_, _, missing = DrawCardsByCodes([]string{"AS", "AS", "AS"}, shoe)
+	require.Equal(t, []string{"AS"}, missing)
+}
+
+func TestRemovePositions(t *testing.T) {
+	require.Equal(t, []string{"KD"}, RemovePositions([]uint{2, 0}, []string{"AS", "KD", "AS"}))
+	require.Equal(t, []string{"AS", "KD"}, RemovePositions(nil, []string{"AS", "KD"}))
+	require.Empty(t, RemovePositions([]uint{0}, []string{"AS"}))
+}
+
+func TestExcessCodes(t *testing.T) {
+	require.Empty(t, ExcessCodes([]string{"AS", "AS"}, []string{"AS", "KD", "AS"}))
+	require.Equal(t, []string{"AS"}, ExcessCodes([]string{"AS", "AS"}, []string{"AS", "KD"}))
+	require.Equal(t, []string{"QH"}, ExcessCodes([]string{"QH"}, []string{"AS", "KD"}))
+}
+
+func TestSubtractCodes(t *testing.T) {
+	codes := []string{"AS", "KD", "AS", "2C"}
+
+	require.Equal(t, []string{"KD", "AS"}, SubtractCodes(codes, []string{"2C", "AS"}))
+	require.Equal(t, []string{"KD", "2C"}, SubtractCodes(codes, []string{"AS", "AS", "AS"}))
+	require.Equal(t, []string{"AS", "KD", "AS", "2C"}, codes)
+}
+
+func TestReturnCodes(t *testing.T) {
+	var (
+		result []string
+		err    error
+	)
+
+	result, err = ReturnCodes("", []string{"AS", "KD"}, []string{"2C", "10H"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AS", "KD", "2C", "10H"}, result)
+
+	result, err = ReturnCodes(ReturnModeBottom, []string{"AS", "KD"}, []string{"2C", "10H"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2C", "10H", "AS", "KD"}, result)
+
+	result, err = ReturnCodes(ReturnModeRandom, []string{"AS", "KD"}, []string{"2C", "10H"}, deckhelper.NewSeededRandom(1, 0))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"2C", "10H", "AS", "KD"}, result)
+	require.Equal(t, []string{"2C", "10H"}, RemoveDrawnCodes([]string{"AS", "KD"}, result))
+
+	result, err = ReturnCodes(ReturnModeRandom, []string{"AS"}, []string{}, deckhelper.NewSeededRandom(1, 0))
+	require.NoError(t, err)
+	require.Equal(t, []string{"AS"}, result)
+
+	_, err = ReturnCodes("middle", []string{"AS"}, []string{}, nil)
+	require.EqualError(t, err, `unknown return mode "middle"`)
+}
+
+func TestMissingCodes(t *testing.T) {
+	require.Equal(t, []string{"QH"}, MissingCodes([]string{"AS", "QH"}, []string{"AS", "KD"}))
+	require.Empty(t, MissingCodes([]string{"AS"}, []string{"AS", "KD"}))
 }
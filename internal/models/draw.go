@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Possible sources of drawn cards
+const (
+	DrawSourceDeck = "deck"
+	DrawSourcePile = "pile"
+)
+
+// Possible kinds of deck history records
+const (
+	// DrawKindDraw is cards taken out of deck or pile
+	DrawKindDraw = "draw"
+	// DrawKindPeek is cards seen without taking them out of deck
+	DrawKindPeek = "peek"
+)
+
+type (
+	// Draw is a type that represents
+	// the model of the deck_draws table.
+	// Batch is a sequence number of draw within deck starting from 1
+	Draw struct {
+		DeckID    string         `json:"-" db:"deck_id"`
+		Batch     uint           `json:"batch" db:"batch"`
+		Kind      string         `json:"kind" db:"kind"`
+		Source    string         `json:"source" db:"source"`
+		Pile      *string        `json:"pile,omitempty" db:"pile"`
+		Cards     Cards          `json:"cards"`
+		CardCodes pq.StringArray `json:"-" db:"card_codes"`
+		Requester *string        `json:"requester,omitempty" db:"requester"`
+		CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	}
+
+	// DrawHistory is a type that represents
+	// page of deck draws ordered by batch
+	DrawHistory struct {
+		DeckID string  `json:"deck_id"`
+		Total  uint    `json:"total"`
+		Limit  uint    `json:"limit"`
+		Offset uint    `json:"offset"`
+		Draws  []*Draw `json:"draws"`
+	}
+)
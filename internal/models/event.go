@@ -0,0 +1,271 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Possible deck event types
+const (
+	EventCreated      = "created"
+	EventShuffled     = "shuffled"
+	EventSorted       = "sorted"
+	EventDrawn        = "drawn"
+	EventDealt        = "dealt"
+	EventReturned     = "returned"
+	EventMovedToPile  = "moved_to_pile"
+	EventPileShuffled = "pile_shuffled"
+	EventClosed       = "closed"
+	EventUndone       = "undone"
+	// EventSnapshot is a state of deck created before events were stored
+	EventSnapshot = "snapshot"
+)
+
+type (
+	// DeckEvent is a type that represents
+	// the model of the deck_events table.
+	// Events are numbered by deck version, so the first event is 1.
+	// Deck row is a fold of its events applied one by one
+	DeckEvent struct {
+		DeckID    string    `json:"deck_id" db:"deck_id"`
+		Number    uint      `json:"number" db:"event_number"`
+		Type      string    `json:"type" db:"event_type"`
+		Data      EventData `json:"data" db:"data"`
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+		// pilesBefore keeps cards of piles before event, so several saves
+		// of the same pile are recorded as a single change
+		pilesBefore map[string][]string
+	}
+
+	// EventData is a type that represents changes made by event, stored as JSON.
+	// Created and snapshot events hold the whole state of deck and its piles,
+	// other events hold changes of codes of deck and piles only
+	EventData struct {
+		// Codes are cards drawn, dealt, returned or moved to pile by event
+		Codes []string `json:"codes,omitempty"`
+		// Pile is a name of pile concerned by event
+		Pile string `json:"pile,omitempty"`
+		// Cards is a change of remaining cards of deck
+		Cards Change `json:"cards"`
+		// IsShuffled is a state of deck after created, snapshot and undone events
+		IsShuffled bool `json:"is_shuffled,omitempty"`
+		// IsClosed is a state of deck after snapshot event
+		IsClosed bool `json:"is_closed,omitempty"`
+		// Random is a random sequence of deck after event if event has changed it
+		Random *RandomState `json:"random,omitempty"`
+		// ClearPiles is set if all piles are removed before Piles are changed
+		ClearPiles bool `json:"clear_piles,omitempty"`
+		// Piles are changes of cards of piles by their names
+		Piles map[string]*Change `json:"piles,omitempty"`
+		// UndoneTo is a number of event which state undone event has restored
+		UndoneTo uint `json:"undone_to,omitempty"`
+	}
+
+	// RandomState is a type that represents seed and step of random sequence of deck
+	RandomState struct {
+		Seed *int64 `json:"seed,omitempty"`
+		Step uint   `json:"step"`
+	}
+)
+
+// NewDeckEvent creates event of given type
+func NewDeckEvent(eventType string) *DeckEvent {
+	return &DeckEvent{Type: eventType}
+}
+
+// SetCreated records the whole state of created deck
+func (e *DeckEvent) SetCreated(deck *Deck) {
+	e.DeckID = deck.DeckID
+	e.Number = deck.Version
+	e.Data.Cards = Change{Order: append([]string{}, deck.CardCodes...)}
+	e.Data.IsShuffled = deck.IsShuffled
+	e.Data.IsClosed = deck.IsClosed
+	e.Data.Random = &RandomState{Seed: deck.Seed, Step: deck.RandomStep}
+}
+
+// SetChanges records changes of deck made by event,
+// number of event is the next version of deck
+func (e *DeckEvent) SetChanges(before, after *Deck) {
+	e.DeckID = before.DeckID
+	e.Number = before.Version + 1
+	e.Data.Cards = DiffCodes(before.CardCodes, after.CardCodes)
+	if e.Type == EventUndone {
+		e.Data.IsShuffled = after.IsShuffled
+	}
+	if !sameSeed(before.Seed, after.Seed) || before.RandomStep != after.RandomStep {
+		e.Data.Random = &RandomState{Seed: after.Seed, Step: after.RandomStep}
+	}
+}
+
+// RecordPile records new cards of pile which had given cards before event
+func (e *DeckEvent) RecordPile(name string, before, after []string) {
+	if e.pilesBefore == nil {
+		e.pilesBefore = make(map[string][]string)
+	}
+	if e.Data.Piles == nil {
+		e.Data.Piles = make(map[string]*Change)
+	}
+	if _, ok := e.Data.Piles[name]; !ok {
+		e.pilesBefore[name] = append([]string{}, before...)
+	}
+	change := DiffCodes(e.pilesBefore[name], after)
+	e.Data.Piles[name] = &change
+}
+
+// ClearAllPiles records removal of all piles, piles recorded after it are kept
+func (e *DeckEvent) ClearAllPiles() {
+	e.Data.ClearPiles = true
+	e.Data.Piles = nil
+	e.pilesBefore = nil
+}
+
+// Apply folds event into state of deck and its piles by event type.
+// Piles are not changed if piles map is nil
+func (e *DeckEvent) Apply(deck *Deck, piles map[string]*Pile) error {
+	data := &e.Data
+	switch e.Type {
+	case EventCreated, EventSnapshot:
+		if data.Cards.Order == nil && (len(data.Cards.Removed) > 0 || len(data.Cards.Inserted) > 0) {
+			return fmt.Errorf("event %d of type %q should hold whole order of deck", e.Number, e.Type)
+		}
+		deck.CardCodes = nil
+		deck.IsShuffled = data.IsShuffled
+		deck.IsClosed = data.IsClosed
+		deck.Seed = nil
+		deck.RandomStep = 0
+	case EventShuffled:
+		deck.IsShuffled = true
+	case EventSorted:
+		deck.IsShuffled = false
+	case EventClosed:
+		deck.IsClosed = true
+	case EventUndone:
+		deck.IsShuffled = data.IsShuffled
+	case EventDrawn, EventDealt, EventReturned, EventMovedToPile, EventPileShuffled:
+	default:
+		return fmt.Errorf("event %d has unknown type %q", e.Number, e.Type)
+	}
+
+	codes, err := data.Cards.Apply(deck.CardCodes)
+	if err != nil {
+		return fmt.Errorf("event %d: %v", e.Number, err)
+	}
+	deck.CardCodes = codes
+	deck.Remaining = uint(len(codes))
+	if data.Random != nil {
+		deck.Seed = data.Random.Seed
+		deck.RandomStep = data.Random.Step
+	}
+	deck.Version = e.Number
+	deck.UpdatedAt = e.CreatedAt
+
+	if piles == nil {
+		return nil
+	}
+	if data.ClearPiles {
+		for name := range piles {
+			delete(piles, name)
+		}
+	}
+	for name, change := range data.Piles {
+		pile, ok := piles[name]
+		if !ok {
+			pile = &Pile{DeckID: deck.DeckID, Name: name, CreatedAt: e.CreatedAt}
+			piles[name] = pile
+		}
+		if pile.CardCodes, err = change.Apply(pile.CardCodes); err != nil {
+			return fmt.Errorf("event %d, pile %q: %v", e.Number, name, err)
+		}
+		pile.Remaining = uint(len(pile.CardCodes))
+		pile.UpdatedAt = e.CreatedAt
+	}
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (d EventData) Value() (driver.Value, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (d *EventData) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, d)
+	case string:
+		return json.Unmarshal([]byte(src), d)
+	default:
+		return fmt.Errorf("cannot scan %T into EventData", src)
+	}
+}
+
+// ReplayDeck rebuilds state of deck and its piles at given event number
+// by folding events ordered by number, the first event should be created or snapshot one.
+// Fields which never change after creation (type, original codes, etc.)
+// are taken from given deck, which is not modified
+func ReplayDeck(deck *Deck, events []*DeckEvent, number uint) (*Deck, []*Pile, error) {
+	replayed := *deck
+	replayed.CardCodes = nil
+	piles := make(map[string]*Pile)
+	applied := false
+	for i, event := range events {
+		if event.Number > number {
+			break
+		}
+		if i == 0 && event.Type != EventCreated && event.Type != EventSnapshot {
+			return nil, nil, fmt.Errorf("event %d is not a start of deck events", event.Number)
+		}
+		if i > 0 && event.Number != events[i-1].Number+1 {
+			return nil, nil, fmt.Errorf("event %d not found", events[i-1].Number+1)
+		}
+		if err := event.Apply(&replayed, piles); err != nil {
+			return nil, nil, err
+		}
+		applied = event.Number == number
+	}
+	if !applied {
+		return nil, nil, fmt.Errorf("event %d not found", number)
+	}
+
+	result := make([]*Pile, 0, len(piles))
+	for _, pile := range piles {
+		result = append(result, pile)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return &replayed, result, nil
+}
+
+// SameState checks if decks have the same state changed by events
+func (d *Deck) SameState(other *Deck) bool {
+	if len(d.CardCodes) != len(other.CardCodes) {
+		return false
+	}
+	for i := range d.CardCodes {
+		if d.CardCodes[i] != other.CardCodes[i] {
+			return false
+		}
+	}
+	return d.IsShuffled == other.IsShuffled &&
+		d.IsClosed == other.IsClosed &&
+		sameSeed(d.Seed, other.Seed) &&
+		d.RandomStep == other.RandomStep
+}
+
+func sameSeed(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
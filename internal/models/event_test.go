@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayDeck(t *testing.T) {
+	seed := int64(7)
+	deck := &Deck{
+		DeckID:        "deck",
+		Type:          "standard",
+		OriginalCodes: []string{"AS", "KD", "2C"},
+		CardCodes:     []string{"AS", "KD", "2C"},
+		Seed:          &seed,
+		Version:       1,
+	}
+
+	created := NewDeckEvent(EventCreated)
+	created.SetCreated(deck)
+
+	drawn := NewDeckEvent(EventDrawn)
+	next := *deck
+	next.CardCodes = []string{"KD", "2C"}
+	next.RandomStep = 1
+	drawn.SetChanges(deck, &next)
+	drawn.RecordPile("hand", nil, []string{"AS"})
+	require.Equal(t, Change{Removed: []uint{0}}, drawn.Data.Cards)
+	require.Equal(t, &RandomState{Seed: &seed, Step: 1}, drawn.Data.Random)
+
+	shuffled := NewDeckEvent(EventShuffled)
+	next.Version = 2
+	shuffledDeck := next
+	shuffledDeck.CardCodes = []string{"2C", "AS", "KD"}
+	shuffledDeck.IsShuffled = true
+	shuffled.SetChanges(&next, &shuffledDeck)
+	shuffled.ClearAllPiles()
+	require.Nil(t, shuffled.Data.Random)
+
+	events := []*DeckEvent{created, drawn, shuffled}
+	for _, event := range events {
+		value, err := event.Data.Value()
+		require.NoError(t, err)
+		event.Data = EventData{}
+		require.NoError(t, event.Data.Scan([]byte(value.(string))))
+	}
+	require.Equal(t, []uint{1, 2, 3}, []uint{created.Number, drawn.Number, shuffled.Number})
+
+	replayed, piles, err := ReplayDeck(&shuffledDeck, events, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"KD", "2C"}, []string(replayed.CardCodes))
+	require.Equal(t, uint(2), replayed.Remaining)
+	require.Equal(t, uint(2), replayed.Version)
+	require.Equal(t, uint(1), replayed.RandomStep)
+	require.Equal(t, "standard", replayed.Type)
+	require.False(t, replayed.IsShuffled)
+	require.Len(t, piles, 1)
+	require.Equal(t, "hand", piles[0].Name)
+	require.Equal(t, []string{"AS"}, []string(piles[0].CardCodes))
+
+	replayed, piles, err = ReplayDeck(&shuffledDeck, events, 3)
+	require.NoError(t, err)
+	require.True(t, replayed.SameState(&shuffledDeck))
+	require.Empty(t, piles)
+
+	// given deck is not changed
+	require.Equal(t, []string{"2C", "AS", "KD"}, []string(shuffledDeck.CardCodes))
+
+	_, _, err = ReplayDeck(deck, events, 4)
+	require.EqualError(t, err, "event 4 not found")
+	_, _, err = ReplayDeck(deck, events[1:], 2)
+	require.EqualError(t, err, "event 2 is not a start of deck events")
+	_, _, err = ReplayDeck(deck, []*DeckEvent{created, shuffled}, 3)
+	require.EqualError(t, err, "event 2 not found")
+	_, _, err = ReplayDeck(deck, []*DeckEvent{created, {Number: 2, Type: "unknown"}}, 2)
+	require.EqualError(t, err, `event 2 has unknown type "unknown"`)
+}
+
+func TestDeckEvent_RecordPile(t *testing.T) {
+	event := NewDeckEvent(EventMovedToPile)
+	event.RecordPile("hand", []string{"AS"}, []string{"AS", "KD"})
+	event.RecordPile("hand", []string{"AS", "KD"}, []string{"2C", "AS", "KD"})
+	require.Equal(t, map[string]*Change{
+		"hand": {Inserted: []uint{0, 2}, Codes: []string{"2C", "KD"}},
+	}, event.Data.Piles)
+
+	event.ClearAllPiles()
+	event.RecordPile("discard", nil, []string{"KD"})
+	require.True(t, event.Data.ClearPiles)
+	require.Equal(t, map[string]*Change{
+		"discard": {Inserted: []uint{0}, Codes: []string{"KD"}},
+	}, event.Data.Piles)
+
+	var scanned EventData
+	require.Error(t, scanned.Scan(1))
+
+	b, err := json.Marshal(EventData{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"cards": {}}`, string(b))
+}
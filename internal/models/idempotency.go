@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyKey is a type that represents
+// the model of the idempotency_keys table.
+// Status is 0 while first request with the key is in progress.
+type IdempotencyKey struct {
+	ClientID    string    `db:"client_id"`
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	Status      int       `db:"status"`
+	Headers     []byte    `db:"headers"`
+	Body        []byte    `db:"body"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+// IsCompleted checks if response for the key is stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.Status != 0
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Pile is a type that represents
+// the model of the piles table.
+type Pile struct {
+	DeckID    string         `json:"deck_id" db:"deck_id"`
+	Name      string         `json:"name" db:"name"`
+	Remaining uint           `json:"remaining" db:"remaining"`
+	Cards     Cards          `json:"cards,omitempty"`
+	CardCodes pq.StringArray `json:"-" db:"card_codes"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// PilesCodes returns codes of all cards held in given piles
+func PilesCodes(piles []*Pile) []string {
+	var codes []string
+	for _, pile := range piles {
+		codes = append(codes, pile.CardCodes...)
+	}
+	return codes
+}
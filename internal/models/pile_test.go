@@ -0,0 +1,18 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPilesCodes(t *testing.T) {
+	require.Empty(t, PilesCodes(nil))
+
+	piles := []*Pile{
+		{Name: "discard", CardCodes: []string{"AS", "KD"}},
+		{Name: "hand", CardCodes: []string{"2C"}},
+		{Name: "empty"},
+	}
+	require.Equal(t, []string{"AS", "KD", "2C"}, PilesCodes(piles))
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+)
+
+// DeckProof is a type that represents commitment of provably fair deck.
+// Server seed and order of deck at creation are revealed once deck is finished or closed
+type DeckProof struct {
+	DeckID     string   `json:"deck_id"`
+	Commitment string   `json:"commitment"`
+	ClientSeed string   `json:"client_seed"`
+	Revealed   bool     `json:"revealed"`
+	ServerSeed string   `json:"server_seed,omitempty"`
+	Codes      []string `json:"codes,omitempty"`
+	Order      []string `json:"order,omitempty"`
+}
+
+// IsProvablyFair checks if deck was shuffled by provably fair generator at creation
+func (d *Deck) IsProvablyFair() bool {
+	return d.Commitment != nil
+}
+
+// IsRevealed checks if provably fair deck is finished or closed,
+// so its server seed and order can be shown
+func (d *Deck) IsRevealed() bool {
+	return d.Remaining == 0 || d.IsClosed
+}
+
+// HidesOrder checks if remaining cards of deck cannot be shown: deck is created
+// with no_peek or it is provably fair deck which order is not revealed yet
+func (d *Deck) HidesOrder() bool {
+	return d.NoPeek || d.IsProvablyFair() && !d.IsRevealed()
+}
+
+// ShuffleFair shuffles original codes of deck by provably fair generator
+// of given seeds and publishes commitment to the result, rng of deck is set to fair one
+func (d *Deck) ShuffleFair(serverSeed, clientSeed string) {
+	d.RNG = deckhelper.RNGFair
+	d.CardCodes = deckhelper.FairShuffle(d.OriginalCodes, serverSeed, clientSeed)
+	commitment := deckhelper.Commitment(serverSeed, d.CardCodes)
+	d.ServerSeed = &serverSeed
+	d.ClientSeed = &clientSeed
+	d.Commitment = &commitment
+	d.IsShuffled = true
+}
+
+// Proof returns proof of provably fair deck, server seed
+// and order are revealed only if deck is finished or closed
+func (d *Deck) Proof() (*DeckProof, error) {
+	if !d.IsProvablyFair() || d.ServerSeed == nil || d.ClientSeed == nil {
+		return nil, fmt.Errorf("deck is not provably fair")
+	}
+
+	proof := &DeckProof{
+		DeckID:     d.DeckID,
+		Commitment: *d.Commitment,
+		ClientSeed: *d.ClientSeed,
+		Revealed:   d.IsRevealed(),
+	}
+	if proof.Revealed {
+		proof.ServerSeed = *d.ServerSeed
+		proof.Codes = d.OriginalCodes
+		proof.Order = deckhelper.FairShuffle(d.OriginalCodes, *d.ServerSeed, *d.ClientSeed)
+	}
+
+	return proof, nil
+}
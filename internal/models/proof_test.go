@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeck_Proof(t *testing.T) {
+	_, err := (&Deck{}).Proof()
+	require.EqualError(t, err, "deck is not provably fair")
+
+	codes := deckhelper.CreateDefaultCodes()
+	deck := &Deck{DeckID: "deck", OriginalCodes: codes}
+	deck.ShuffleFair("server", "client")
+	deck.Remaining = uint(len(deck.CardCodes))
+	require.True(t, deck.IsProvablyFair())
+	require.True(t, deck.IsShuffled)
+	require.Equal(t, deckhelper.RNGFair, deck.RNG)
+	order := append([]string(nil), deck.CardCodes...)
+
+	proof, err := deck.Proof()
+	require.NoError(t, err)
+	require.Equal(t, &DeckProof{
+		DeckID:     "deck",
+		Commitment: deckhelper.Commitment("server", order),
+		ClientSeed: "client",
+	}, proof)
+
+	deck.IsClosed = true
+	proof, err = deck.Proof()
+	require.NoError(t, err)
+	require.True(t, proof.Revealed)
+	require.Equal(t, "server", proof.ServerSeed)
+	require.Equal(t, order, proof.Order)
+	require.NoError(t, deckhelper.VerifyFairShuffle(proof.Codes, proof.ServerSeed, proof.ClientSeed, proof.Commitment, proof.Order))
+}
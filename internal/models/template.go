@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+)
+
+// TemplateDeckType is a type of decks created from user-defined template
+const TemplateDeckType = "template"
+
+type (
+	// Template is a type that represents
+	// the model of the deck_templates table.
+	Template struct {
+		TemplateID string        `json:"template_id" db:"template_id"`
+		Name       string        `json:"name" db:"name"`
+		Cards      TemplateCards `json:"cards" db:"cards"`
+		CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+		UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+	}
+
+	// TemplateCard is a type that represents
+	// single card of user-defined template
+	TemplateCard struct {
+		Code       string                 `json:"code"`
+		Name       string                 `json:"name"`
+		Suit       string                 `json:"suit,omitempty"`
+		Attributes map[string]interface{} `json:"attributes,omitempty"`
+	}
+
+	// TemplateCards is a type that represents
+	// list of template cards stored as JSON
+	TemplateCards []TemplateCard
+)
+
+// Definition converts template to deck definition, cards keep template order
+func (t *Template) Definition() (*deckhelper.DeckDefinition, error) {
+	def := &deckhelper.DeckDefinition{
+		Name:   t.Name,
+		Extras: make([]deckhelper.CardDefinition, 0, len(t.Cards)),
+	}
+	for _, card := range t.Cards {
+		if card.Name == "" {
+			return nil, fmt.Errorf("card %q name is required", card.Code)
+		}
+		def.Extras = append(def.Extras, deckhelper.CardDefinition{
+			Code:       card.Code,
+			Value:      card.Name,
+			Suit:       card.Suit,
+			Attributes: card.Attributes,
+		})
+	}
+
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// Value implements the driver.Valuer interface.
+func (c TemplateCards) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (c *TemplateCards) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, c)
+	case string:
+		return json.Unmarshal([]byte(src), c)
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into TemplateCards", src)
+	}
+}
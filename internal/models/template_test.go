@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Definition(t *testing.T) {
+	template := &Template{
+		Name: "monsters",
+		Cards: TemplateCards{
+			{Code: "DR", Name: "DRAGON", Suit: "FIRE", Attributes: map[string]interface{}{"power": float64(9)}},
+			{Code: "GO", Name: "GOBLIN"},
+		},
+	}
+
+	def, err := template.Definition()
+	require.NoError(t, err)
+	require.Equal(t, []string{"DR", "GO"}, def.Codes())
+
+	cards, err := BuildDeckCards(def, []string{"GO", "DR", "X1"})
+	require.NoError(t, err)
+	require.EqualValues(t, []*Card{
+		{Value: "GOBLIN", Code: "GO"},
+		{Value: "DRAGON", Suit: "FIRE", Code: "DR", Attributes: map[string]interface{}{"power": float64(9)}},
+		{Value: "JOKER", Code: "X1"},
+	}, cards)
+
+	_, err = (&Template{Name: "unnamed", Cards: TemplateCards{{Code: "DR"}}}).Definition()
+	require.EqualError(t, err, `card "DR" name is required`)
+
+	_, err = (&Template{Name: "empty"}).Definition()
+	require.EqualError(t, err, `deck definition "empty" has no cards`)
+
+	_, err = (&Template{Name: "dup", Cards: TemplateCards{{Code: "DR", Name: "A"}, {Code: "DR", Name: "B"}}}).Definition()
+	require.EqualError(t, err, `deck definition "dup" has duplicated card "DR"`)
+}
+
+func TestTemplateCards_ValueScan(t *testing.T) {
+	cards := TemplateCards{{Code: "DR", Name: "DRAGON", Attributes: map[string]interface{}{"power": float64(9)}}}
+
+	value, err := cards.Value()
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"code":"DR","name":"DRAGON","attributes":{"power":9}}]`, value.(string))
+
+	var scanned TemplateCards
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	require.Equal(t, cards, scanned)
+
+	require.Error(t, scanned.Scan(42))
+}
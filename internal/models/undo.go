@@ -0,0 +1,79 @@
+package models
+
+import "fmt"
+
+// UndoPolicy is a type that represents
+// which decks are allowed to undo their operations
+type UndoPolicy string
+
+// Possible undo policies
+const (
+	// UndoAll allows undo for any deck
+	UndoAll UndoPolicy = "all"
+	// UndoCasual allows undo for decks which are not competitive
+	UndoCasual UndoPolicy = "casual"
+	// UndoNone disables undo for all decks
+	UndoNone UndoPolicy = "none"
+)
+
+// IsValid checks if undo policy is known, empty policy is treated as casual
+func (p UndoPolicy) IsValid() bool {
+	switch p {
+	case "", UndoAll, UndoCasual, UndoNone:
+		return true
+	}
+	return false
+}
+
+// Allows checks if policy allows to undo operations of given deck
+func (p UndoPolicy) Allows(deck *Deck) bool {
+	switch p {
+	case UndoAll:
+		return true
+	case UndoNone:
+		return false
+	default:
+		return !deck.IsCompetitive
+	}
+}
+
+// UndoTarget returns number of event which state deck gets back to
+// when given count of its latest operations is undone.
+// Events should be ordered by number and end with the latest event of deck.
+// Undone operations are skipped, so undo of undo goes further back
+// instead of redoing operations. Creation of deck cannot be undone
+func UndoTarget(events []*DeckEvent, steps uint) (uint, error) {
+	if len(events) == 0 {
+		return 0, fmt.Errorf("deck has no events")
+	}
+
+	byNumber := make(map[uint]*DeckEvent, len(events))
+	for _, event := range events {
+		byNumber[event.Number] = event
+	}
+
+	target := events[len(events)-1].Number
+	for step := uint(0); step <= steps; step++ {
+		event, ok := byNumber[target]
+		for ok && event.Type == EventUndone {
+			target = event.Data.UndoneTo
+			event, ok = byNumber[target]
+		}
+		if !ok {
+			return 0, fmt.Errorf("event %d not found", target)
+		}
+		if step == steps {
+			break
+		}
+
+		if event.Type == EventCreated || event.Type == EventSnapshot {
+			if step == 0 {
+				return 0, fmt.Errorf("nothing to undo")
+			}
+			return 0, fmt.Errorf("only %d operations can be undone", step)
+		}
+		target = event.Number - 1
+	}
+
+	return target, nil
+}
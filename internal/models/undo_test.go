@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoPolicy_Allows(t *testing.T) {
+	casual := &Deck{}
+	competitive := &Deck{IsCompetitive: true}
+
+	require.True(t, UndoAll.Allows(competitive))
+	require.True(t, UndoCasual.Allows(casual))
+	require.False(t, UndoCasual.Allows(competitive))
+	require.False(t, UndoPolicy("").Allows(competitive))
+	require.False(t, UndoNone.Allows(casual))
+
+	require.True(t, UndoPolicy("").IsValid())
+	require.False(t, UndoPolicy("sometimes").IsValid())
+}
+
+func TestUndoTarget(t *testing.T) {
+	events := []*DeckEvent{
+		{Number: 1, Type: EventCreated},
+		{Number: 2, Type: EventShuffled},
+		{Number: 3, Type: EventDrawn},
+		{Number: 4, Type: EventDrawn},
+	}
+
+	target, err := UndoTarget(events, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), target)
+
+	target, err = UndoTarget(events, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), target)
+
+	_, err = UndoTarget(events, 4)
+	require.EqualError(t, err, "only 3 operations can be undone")
+
+	// undo of undo goes further back
+	events = append(events, &DeckEvent{Number: 5, Type: EventUndone, Data: EventData{UndoneTo: 3}})
+	target, err = UndoTarget(events, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), target)
+
+	// operations after undo are undone first,
+	// state after undo is the one of event it got back to
+	events = append(events, &DeckEvent{Number: 6, Type: EventReturned})
+	target, err = UndoTarget(events, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), target)
+	target, err = UndoTarget(events, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint(2), target)
+
+	_, err = UndoTarget([]*DeckEvent{{Number: 7, Type: EventSnapshot}}, 1)
+	require.EqualError(t, err, "nothing to undo")
+	_, err = UndoTarget(nil, 1)
+	require.Error(t, err)
+}
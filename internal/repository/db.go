@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+
 	sq "github.com/Masterminds/squirrel"
+	"github.com/card-deck/internal/models"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 )
@@ -10,13 +13,20 @@ var sb = NewBuilder()
 
 // Repository represents type to handle database operations
 type Repository struct {
-	db     *sqlx.DB
+	conn *sqlx.DB
+	// db is either connection or transaction all queries are executed with
+	db     sqlx.Ext
 	logger *zap.Logger
+	// event collects changes of piles made within ModifyDeck
+	event *models.DeckEvent
+	// ctx bounds transactions started by repository
+	ctx context.Context
 }
 
 // NewRepository creates new instance of Repository
 func NewRepository(db *sqlx.DB, logger *zap.Logger) *Repository {
 	return &Repository{
+		conn:   db,
 		db:     db,
 		logger: logger,
 	}
@@ -26,3 +36,38 @@ func NewRepository(db *sqlx.DB, logger *zap.Logger) *Repository {
 func NewBuilder() sq.StatementBuilderType {
 	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 }
+
+// WithContext returns repository which transactions are bound to given context,
+// transaction is rolled back if context is done before it's committed
+func (r *Repository) WithContext(ctx context.Context) *Repository {
+	bound := *r
+	bound.ctx = ctx
+	return &bound
+}
+
+// InTx executes fn with repository bound to a single transaction,
+// transaction is committed if fn succeeds and rolled back otherwise.
+// Nested calls reuse already started transaction.
+func (r *Repository) InTx(fn func(tx *Repository) error) error {
+	if _, ok := r.db.(*sqlx.Tx); ok {
+		return fn(r)
+	}
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tx, err := r.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err = fn(&Repository{conn: r.conn, db: tx, logger: r.logger, ctx: ctx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -2,31 +2,98 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/card-deck/internal/models"
 	"github.com/card-deck/pkg/errors"
+	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
 )
 
 const decksTable = "decks"
 
-// CreateDeck creates new deck
+// CreateDeck creates new deck along with its created event.
+// Deck created from template is checked against template locked in the same transaction,
+// so concurrent update of template cannot leave deck with codes template doesn't define
 func (r *Repository) CreateDeck(deck *models.Deck) error {
+	return r.InTx(func(tx *Repository) error {
+		if deck.TemplateID != nil {
+			if err := tx.checkTemplateCodes(*deck.TemplateID, deck.OriginalCodes, deck.DecksCount); err != nil {
+				return err
+			}
+		}
+		if err := tx.insertDeck(deck); err != nil {
+			return err
+		}
+
+		event := models.NewDeckEvent(models.EventCreated)
+		event.SetCreated(deck)
+		return tx.AddEvent(event)
+	})
+}
+
+// insertDeck sets ID, version and timestamps of new deck and stores it
+func (r *Repository) insertDeck(deck *models.Deck) error {
 	now := time.Now().UTC()
 	deck.CreatedAt = now
 	deck.UpdatedAt = now
 	deck.DeckID = uuid.NewV4().String()
 	deck.Remaining = uint(len(deck.CardCodes))
+	deck.Version = 1
 
 	_, err := sb.Insert(decksTable).
 		SetMap(map[string]interface{}{
-			"deck_id":     deck.DeckID,
+			"deck_id":        deck.DeckID,
+			"deck_type":      deck.Type,
+			"is_shuffled":    deck.IsShuffled,
+			"decks_count":    deck.DecksCount,
+			"template_id":    deck.TemplateID,
+			"remaining":      deck.Remaining,
+			"card_codes":     deck.CardCodes,
+			"original_codes": deck.OriginalCodes,
+			"rng":            deck.RNG,
+			"seed":           deck.Seed,
+			"random_step":    deck.RandomStep,
+			"commitment":     deck.Commitment,
+			"client_seed":    deck.ClientSeed,
+			"server_seed":    deck.ServerSeed,
+			"is_closed":      deck.IsClosed,
+			"is_competitive": deck.IsCompetitive,
+			"no_peek":        deck.NoPeek,
+			"version":        deck.Version,
+			"created_at":     deck.CreatedAt,
+			"updated_at":     deck.UpdatedAt,
+		}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDeckByID returns deck by it's ID
+func (r *Repository) GetDeckByID(deckID string) (*models.Deck, error) {
+	return r.getDeck(deckID, false)
+}
+
+// updateDeck stores state of deck projected from its events by it's ID
+func (r *Repository) updateDeck(deck *models.Deck) error {
+	_, err := sb.Update(decksTable).
+		Where(sq.Eq{
+			"deck_id": deck.DeckID,
+		}).
+		SetMap(map[string]interface{}{
 			"is_shuffled": deck.IsShuffled,
 			"remaining":   deck.Remaining,
 			"card_codes":  deck.CardCodes,
-			"created_at":  deck.CreatedAt,
+			"seed":        deck.Seed,
+			"random_step": deck.RandomStep,
+			"is_closed":   deck.IsClosed,
+			"version":     deck.Version,
 			"updated_at":  deck.UpdatedAt,
 		}).
 		RunWith(r.db).
@@ -34,29 +101,126 @@ func (r *Repository) CreateDeck(deck *models.Deck) error {
 	if err != nil {
 		return err
 	}
-
 	return nil
 }
 
-// GetDeckByID returns deck by it's ID
-func (r *Repository) GetDeckByID(deckID string) (*models.Deck, error) {
-	query, args, err := sb.Select(
+// ModifyDeck locks deck by it's ID for the time of transaction,
+// applies modify func to it and stores changes it made as given event.
+// Deck row is built by applying the event to deck state before modify func,
+// so the row is always the fold of deck events.
+// Concurrent modifications of the same deck are executed one by one,
+// so each of them sees changes made by previous one.
+// modify func receives repository bound to the same transaction,
+// piles saved or deleted through it are recorded into event.
+// Error returned by modify func is returned as is and rollbacks transaction.
+func (r *Repository) ModifyDeck(
+	deckID string,
+	event *models.DeckEvent,
+	modify func(tx *Repository, deck *models.Deck) error,
+) (*models.Deck, error) {
+	var deck *models.Deck
+	err := r.InTx(func(tx *Repository) error {
+		var err error
+		deck, err = tx.getDeck(deckID, true)
+		if err != nil {
+			return err
+		}
+
+		before := *deck
+		before.CardCodes = append([]string(nil), deck.CardCodes...)
+
+		recorder := *tx
+		recorder.event = event
+		if err = modify(&recorder, deck); err != nil {
+			return err
+		}
+
+		event.SetChanges(&before, deck)
+		event.CreatedAt = time.Now().UTC()
+		projected := before
+		if err = event.Apply(&projected, nil); err != nil {
+			return err
+		}
+		if !projected.SameState(deck) {
+			return fmt.Errorf("event %d of type %q doesn't match changes of deck", event.Number, event.Type)
+		}
+		if err = tx.updateDeck(&projected); err != nil {
+			return err
+		}
+		deck.Remaining = projected.Remaining
+		deck.Version = projected.Version
+		deck.UpdatedAt = projected.UpdatedAt
+
+		return tx.AddEvent(event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+// LockDeck locks deck by it's ID for the time of transaction and calls fn with it.
+// Unlike ModifyDeck deck is not updated and no event is stored,
+// it is used to record actions which don't change deck, e.g. peeks
+func (r *Repository) LockDeck(deckID string, fn func(tx *Repository, deck *models.Deck) error) (*models.Deck, error) {
+	var deck *models.Deck
+	err := r.InTx(func(tx *Repository) error {
+		var err error
+		deck, err = tx.getDeck(deckID, true)
+		if err != nil {
+			return err
+		}
+		return fn(tx, deck)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+// getDeck returns deck by it's ID, row is locked till the end
+// of transaction if forUpdate is set
+func (r *Repository) getDeck(deckID string, forUpdate bool) (*models.Deck, error) {
+	if _, err := uuid.FromString(deckID); err != nil {
+		return nil, errors.New(errors.NotFound, "deck not found")
+	}
+
+	builder := sb.Select(
 		"deck_id",
-		"updated_at",
+		"deck_type",
 		"is_shuffled",
+		"decks_count",
+		"template_id",
 		"remaining",
 		"card_codes",
+		"original_codes",
+		"rng",
+		"seed",
+		"random_step",
+		"commitment",
+		"client_seed",
+		"server_seed",
+		"is_closed",
+		"is_competitive",
+		"no_peek",
+		"version",
 		"created_at",
 		"updated_at",
 	).From(decksTable).
-		Where(sq.Eq{"deck_id": deckID}).
-		ToSql()
+		Where(sq.Eq{"deck_id": deckID})
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
 	var deck models.Deck
-	if err = r.db.Get(&deck, query, args...); err != nil {
+	if err = sqlx.Get(r.db, &deck, query, args...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New(errors.NotFound, "deck not found")
 		}
@@ -65,26 +229,3 @@ func (r *Repository) GetDeckByID(deckID string) (*models.Deck, error) {
 
 	return &deck, nil
 }
-
-// UpdateDeck updates deck by it's ID
-func (r *Repository) UpdateDeck(deck *models.Deck) error {
-	now := time.Now().UTC()
-	deck.UpdatedAt = now
-	deck.Remaining = uint(len(deck.CardCodes))
-
-	_, err := sb.Update(decksTable).
-		Where(sq.Eq{
-			"deck_id": deck.DeckID,
-		}).
-		SetMap(map[string]interface{}{
-			"remaining":  deck.Remaining,
-			"card_codes": deck.CardCodes,
-			"updated_at": deck.UpdatedAt,
-		}).
-		RunWith(r.db).
-		Exec()
-	if err != nil {
-		return err
-	}
-	return nil
-}
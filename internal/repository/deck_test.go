@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	// required for database
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+// newTestRepository connects to database given by TEST_DB_URL env
+// and applies migrations, test is skipped if env is not set
+func newTestRepository(t *testing.T) *Repository {
+	dbURL := os.Getenv("TEST_DB_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+
+	m, err := migrate.New("file://../../db/migrations", dbURL)
+	require.NoError(t, err)
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		require.NoError(t, err)
+	}
+
+	db, err := sqlx.Connect("postgres", dbURL)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewRepository(db, zap.NewNop())
+}
+
+func TestRepository_ModifyDeck_ConcurrentDraws(t *testing.T) {
+	repo := newTestRepository(t)
+
+	codes := deckhelper.CreateDefaultCodes()
+	deck := &models.Deck{
+		CardCodes:     codes,
+		OriginalCodes: append([]string(nil), codes...),
+	}
+	require.NoError(t, repo.CreateDeck(deck))
+
+	const (
+		workers = 40
+		count   = 2
+	)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		drawn  []string
+		failed int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var got []string
+			_, err := repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventDrawn), func(_ *Repository, deck *models.Deck) error {
+				var err error
+				got, err = models.DrawTopNCards(count, deck.CardCodes)
+				if err != nil {
+					return err
+				}
+				deck.CardCodes = models.RemoveDrawnCodes(got, deck.CardCodes)
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				return
+			}
+			drawn = append(drawn, got...)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, drawn, len(codes))
+	require.ElementsMatch(t, codes, drawn)
+	require.Equal(t, workers-len(codes)/count, failed)
+
+	stored, err := repo.GetDeckByID(deck.DeckID)
+	require.NoError(t, err)
+	require.Zero(t, stored.Remaining)
+	require.Empty(t, stored.CardCodes)
+	require.EqualValues(t, 1+len(codes)/count, stored.Version)
+}
+
+func TestRepository_DeckSeed(t *testing.T) {
+	repo := newTestRepository(t)
+
+	codes := deckhelper.CreateDefaultCodes()
+	deck := &models.Deck{
+		CardCodes:     codes,
+		OriginalCodes: append([]string(nil), codes...),
+	}
+	deck.Reseed(42)
+	require.NoError(t, repo.CreateDeck(deck))
+
+	modified, err := repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventShuffled), func(_ *Repository, deck *models.Deck) error {
+		deckhelper.Shuffle(deck.CardCodes, deck.Random())
+		return nil
+	})
+	require.NoError(t, err)
+
+	stored, err := repo.GetDeckByID(deck.DeckID)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), *stored.Seed)
+	require.Equal(t, uint(1), stored.RandomStep)
+	require.Equal(t, modified.CardCodes, stored.CardCodes)
+}
+
+func TestRepository_DeckProof(t *testing.T) {
+	repo := newTestRepository(t)
+
+	deck := &models.Deck{OriginalCodes: deckhelper.CreateDefaultCodes()}
+	deck.ShuffleFair("server", "client")
+	require.NoError(t, repo.CreateDeck(deck))
+
+	_, err := repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventClosed), func(_ *Repository, deck *models.Deck) error {
+		deck.IsClosed = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	stored, err := repo.GetDeckByID(deck.DeckID)
+	require.NoError(t, err)
+	require.True(t, stored.IsClosed)
+	require.Equal(t, "server", *stored.ServerSeed)
+
+	proof, err := stored.Proof()
+	require.NoError(t, err)
+	require.True(t, proof.Revealed)
+	require.Equal(t, []string(deck.CardCodes), proof.Order)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/card-deck/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+const drawsTable = "deck_draws"
+
+var drawColumns = []string{
+	"deck_id",
+	"batch",
+	"kind",
+	"source",
+	"pile",
+	"card_codes",
+	"requester",
+	"created_at",
+}
+
+// AddDraw appends draw to deck history and sets it's batch number, kind is draw if not set.
+// It should be called while deck is locked, e.g. inside of ModifyDeck or LockDeck
+func (r *Repository) AddDraw(draw *models.Draw) error {
+	if draw.Kind == "" {
+		draw.Kind = models.DrawKindDraw
+	}
+
+	query, args, err := sb.Select("COALESCE(MAX(batch), 0) + 1").
+		From(drawsTable).
+		Where(sq.Eq{"deck_id": draw.DeckID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if err = sqlx.Get(r.db, &draw.Batch, query, args...); err != nil {
+		return err
+	}
+	draw.CreatedAt = time.Now().UTC()
+
+	_, err = sb.Insert(drawsTable).
+		SetMap(map[string]interface{}{
+			"deck_id":    draw.DeckID,
+			"batch":      draw.Batch,
+			"kind":       draw.Kind,
+			"source":     draw.Source,
+			"pile":       draw.Pile,
+			"card_codes": draw.CardCodes,
+			"requester":  draw.Requester,
+			"created_at": draw.CreatedAt,
+		}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDraws returns page of deck draws ordered by batch along with total count of draws
+func (r *Repository) GetDraws(deckID string, limit, offset uint) ([]*models.Draw, uint, error) {
+	query, args, err := sb.Select(drawColumns...).
+		From(drawsTable).
+		Where(sq.Eq{"deck_id": deckID}).
+		OrderBy("batch").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	draws := []*models.Draw{}
+	if err = sqlx.Select(r.db, &draws, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err = sb.Select("COUNT(*)").
+		From(drawsTable).
+		Where(sq.Eq{"deck_id": deckID}).
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total uint
+	if err = sqlx.Get(r.db, &total, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return draws, total, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Draws(t *testing.T) {
+	repo := newTestRepository(t)
+
+	codes := deckhelper.CreateDefaultCodes()
+	deck := &models.Deck{
+		CardCodes:     codes,
+		OriginalCodes: append([]string(nil), codes...),
+	}
+	require.NoError(t, repo.CreateDeck(deck))
+
+	pile := "hand"
+	for _, draw := range []*models.Draw{
+		{DeckID: deck.DeckID, Source: models.DrawSourceDeck, CardCodes: []string{"AS", "2S"}},
+		{DeckID: deck.DeckID, Source: models.DrawSourcePile, Pile: &pile, CardCodes: []string{"AS"}},
+		{DeckID: deck.DeckID, Source: models.DrawSourceDeck, CardCodes: []string{"3S"}},
+	} {
+		_, err := repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventDrawn), func(tx *Repository, _ *models.Deck) error {
+			return tx.AddDraw(draw)
+		})
+		require.NoError(t, err)
+	}
+
+	locked, err := repo.LockDeck(deck.DeckID, func(tx *Repository, _ *models.Deck) error {
+		return tx.AddDraw(&models.Draw{DeckID: deck.DeckID, Kind: models.DrawKindPeek, Source: models.DrawSourceDeck, CardCodes: []string{"4S"}})
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint(4), locked.Version)
+
+	draws, total, err := repo.GetDraws(deck.DeckID, 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint(4), total)
+	require.Len(t, draws, 2)
+	require.Equal(t, uint(2), draws[0].Batch)
+	require.Equal(t, "hand", *draws[0].Pile)
+	require.Equal(t, models.DrawKindDraw, draws[0].Kind)
+	require.Equal(t, uint(3), draws[1].Batch)
+	require.Equal(t, []string{"3S"}, []string(draws[1].CardCodes))
+
+	draws, _, err = repo.GetDraws(deck.DeckID, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, models.DrawKindPeek, draws[0].Kind)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/card-deck/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+const eventsTable = "deck_events"
+
+var eventColumns = []string{
+	"deck_id",
+	"event_number",
+	"event_type",
+	"data",
+	"created_at",
+}
+
+// AddEvent appends event to deck events, number of event should be the new deck version
+func (r *Repository) AddEvent(event *models.DeckEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := sb.Insert(eventsTable).
+		SetMap(map[string]interface{}{
+			"deck_id":      event.DeckID,
+			"event_number": event.Number,
+			"event_type":   event.Type,
+			"data":         event.Data,
+			"created_at":   event.CreatedAt,
+		}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDeckEvents returns deck events up to given number ordered by number
+func (r *Repository) GetDeckEvents(deckID string, upTo uint) ([]*models.DeckEvent, error) {
+	query, args, err := sb.Select(eventColumns...).
+		From(eventsTable).
+		Where(sq.Eq{"deck_id": deckID}).
+		Where(sq.LtOrEq{"event_number": upTo}).
+		OrderBy("event_number").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*models.DeckEvent
+	if err = sqlx.Select(r.db, &events, query, args...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_DeckEvents(t *testing.T) {
+	repo := newTestRepository(t)
+
+	codes := deckhelper.CreateDefaultCodes()
+	deck := &models.Deck{
+		CardCodes:     codes,
+		OriginalCodes: append([]string(nil), codes...),
+	}
+	require.NoError(t, repo.CreateDeck(deck))
+
+	_, err := repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventDrawn), func(tx *Repository, deck *models.Deck) error {
+		deck.CardCodes = deck.CardCodes[2:]
+		return tx.SavePile(&models.Pile{DeckID: deck.DeckID, Name: "hand", CardCodes: codes[:2]})
+	})
+	require.NoError(t, err)
+
+	_, err = repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventShuffled), func(tx *Repository, deck *models.Deck) error {
+		if err := tx.DeletePilesByDeckID(deck.DeckID); err != nil {
+			return err
+		}
+		deck.CardCodes = append([]string(nil), deck.OriginalCodes...)
+		deckhelper.Shuffle(deck.CardCodes, deckhelper.NewSeededRandom(1, 0))
+		return nil
+	})
+	require.NoError(t, err)
+
+	current, err := repo.GetDeckByID(deck.DeckID)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), current.Version)
+
+	events, err := repo.GetDeckEvents(deck.DeckID, 3)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, models.EventCreated, events[0].Type)
+
+	replayed, piles, err := models.ReplayDeck(current, events, 2)
+	require.NoError(t, err)
+	require.Equal(t, codes[2:], []string(replayed.CardCodes))
+	require.Len(t, piles, 1)
+	require.Equal(t, codes[:2], []string(piles[0].CardCodes))
+
+	replayed, piles, err = models.ReplayDeck(current, events, 3)
+	require.NoError(t, err)
+	require.Equal(t, current.CardCodes, replayed.CardCodes)
+	require.Empty(t, piles)
+}
+
+func TestRepository_ReplayEqualsStoredDeck(t *testing.T) {
+	repo := newTestRepository(t)
+
+	codes := deckhelper.CreateDefaultCodes()
+	seed := int64(3)
+	deck := &models.Deck{
+		CardCodes:     append([]string(nil), codes...),
+		OriginalCodes: codes,
+		Seed:          &seed,
+	}
+	require.NoError(t, repo.CreateDeck(deck))
+
+	modifications := []struct {
+		eventType string
+		modify    func(tx *Repository, deck *models.Deck) error
+	}{
+		{models.EventShuffled, func(tx *Repository, deck *models.Deck) error {
+			deck.IsShuffled = true
+			deck.RandomStep++
+			deckhelper.Shuffle(deck.CardCodes, deckhelper.NewSeededRandom(seed, 0))
+			return nil
+		}},
+		{models.EventDrawn, func(tx *Repository, deck *models.Deck) error {
+			hand := append([]string(nil), deck.CardCodes[:3]...)
+			deck.CardCodes = deck.CardCodes[3:]
+			return tx.SavePile(&models.Pile{DeckID: deck.DeckID, Name: "hand", CardCodes: hand})
+		}},
+		{models.EventMovedToPile, func(tx *Repository, deck *models.Deck) error {
+			hand, err := tx.GetPile(deck.DeckID, "hand")
+			if err != nil {
+				return err
+			}
+			discard := hand.CardCodes[:1]
+			hand.CardCodes = hand.CardCodes[1:]
+			if err = tx.SavePile(hand); err != nil {
+				return err
+			}
+			return tx.SavePile(&models.Pile{DeckID: deck.DeckID, Name: "discard", CardCodes: discard})
+		}},
+		{models.EventReturned, func(tx *Repository, deck *models.Deck) error {
+			hand, err := tx.GetPile(deck.DeckID, "hand")
+			if err != nil {
+				return err
+			}
+			deck.CardCodes = append(deck.CardCodes, hand.CardCodes[0])
+			hand.CardCodes = hand.CardCodes[1:]
+			return tx.SavePile(hand)
+		}},
+		{models.EventSorted, func(tx *Repository, deck *models.Deck) error {
+			deck.IsShuffled = false
+			sort.Strings(deck.CardCodes)
+			return nil
+		}},
+		{models.EventClosed, func(tx *Repository, deck *models.Deck) error {
+			deck.IsClosed = true
+			return nil
+		}},
+	}
+	for _, m := range modifications {
+		_, err := repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(m.eventType), m.modify)
+		require.NoError(t, err, m.eventType)
+	}
+
+	stored, err := repo.GetDeckByID(deck.DeckID)
+	require.NoError(t, err)
+	require.Equal(t, uint(len(modifications)+1), stored.Version)
+	storedPiles, err := repo.GetPilesByDeckID(deck.DeckID)
+	require.NoError(t, err)
+	require.Len(t, storedPiles, 2)
+
+	events, err := repo.GetDeckEvents(deck.DeckID, stored.Version)
+	require.NoError(t, err)
+	replayed, piles, err := models.ReplayDeck(stored, events, stored.Version)
+	require.NoError(t, err)
+	require.True(t, replayed.SameState(stored))
+	require.Equal(t, stored.Remaining, replayed.Remaining)
+	require.Equal(t, stored.UpdatedAt.Unix(), replayed.UpdatedAt.Unix())
+	require.Len(t, piles, len(storedPiles))
+	for i := range piles {
+		require.Equal(t, storedPiles[i].Name, piles[i].Name)
+		require.Equal(t, storedPiles[i].CardCodes, piles[i].CardCodes)
+	}
+
+	// modification which doesn't match its event is rejected
+	_, err = repo.ModifyDeck(deck.DeckID, models.NewDeckEvent(models.EventDrawn), func(tx *Repository, deck *models.Deck) error {
+		deck.IsShuffled = true
+		return nil
+	})
+	require.EqualError(t, err, `event 8 of type "drawn" doesn't match changes of deck`)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/pkg/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+const idempotencyKeysTable = "idempotency_keys"
+
+// AcquireIdempotencyKey stores new in progress idempotency key,
+// expired key with the same name is replaced. Key which is still in progress
+// after lease is treated as abandoned (e.g. process died) and is replaced too.
+// Returns false if not expired key already exists.
+// Creation time of acquired key identifies its owner, it is stored with the
+// precision of database, so the key can be completed or deleted by the owner only.
+func (r *Repository) AcquireIdempotencyKey(key *models.IdempotencyKey, ttl, lease time.Duration) (bool, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	key.CreatedAt = now
+	key.ExpiresAt = now.Add(ttl)
+
+	_, err := sb.Delete(idempotencyKeysTable).
+		Where(sq.Eq{"client_id": key.ClientID, "key": key.Key}).
+		Where(sq.Or{
+			sq.Lt{"expires_at": now},
+			sq.And{sq.Eq{"status": 0}, sq.Lt{"created_at": now.Add(-lease)}},
+		}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := sb.Insert(idempotencyKeysTable).
+		SetMap(map[string]interface{}{
+			"client_id":    key.ClientID,
+			"key":          key.Key,
+			"request_hash": key.RequestHash,
+			"created_at":   key.CreatedAt,
+			"expires_at":   key.ExpiresAt,
+		}).
+		Suffix("ON CONFLICT (client_id, key) DO NOTHING").
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return inserted == 1, nil
+}
+
+// GetIdempotencyKey returns idempotency key by client ID and key
+func (r *Repository) GetIdempotencyKey(clientID, key string) (*models.IdempotencyKey, error) {
+	query, args, err := sb.Select(
+		"client_id",
+		"key",
+		"request_hash",
+		"status",
+		"headers",
+		"body",
+		"created_at",
+		"expires_at",
+	).From(idempotencyKeysTable).
+		Where(sq.Eq{"client_id": clientID, "key": key}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var idempotencyKey models.IdempotencyKey
+	if err = sqlx.Get(r.db, &idempotencyKey, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.NotFound, "idempotency key not found")
+		}
+		return nil, err
+	}
+
+	return &idempotencyKey, nil
+}
+
+// CompleteIdempotencyKey stores response for idempotency key acquired by the caller,
+// error is returned if the key has been taken over or removed since it was acquired
+func (r *Repository) CompleteIdempotencyKey(key *models.IdempotencyKey) error {
+	res, err := sb.Update(idempotencyKeysTable).
+		Where(sq.Eq{"client_id": key.ClientID, "key": key.Key, "created_at": key.CreatedAt}).
+		SetMap(map[string]interface{}{
+			"status":  key.Status,
+			"headers": string(key.Headers),
+			"body":    key.Body,
+		}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errors.New(errors.NotFound, "idempotency key not found")
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey removes idempotency key acquired by the caller, so request can be retried.
+// Key taken over by another request is kept
+func (r *Repository) DeleteIdempotencyKey(key *models.IdempotencyKey) error {
+	_, err := sb.Delete(idempotencyKeysTable).
+		Where(sq.Eq{"client_id": key.ClientID, "key": key.Key, "created_at": key.CreatedAt}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
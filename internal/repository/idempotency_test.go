@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/card-deck/internal/models"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_IdempotencyKey(t *testing.T) {
+	repo := newTestRepository(t)
+
+	key := &models.IdempotencyKey{
+		Key:         uuid.NewV4().String(),
+		RequestHash: "hash",
+	}
+
+	acquired, err := repo.AcquireIdempotencyKey(key, time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	acquired, err = repo.AcquireIdempotencyKey(&models.IdempotencyKey{Key: key.Key, RequestHash: "other"}, time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	stored, err := repo.GetIdempotencyKey("", key.Key)
+	require.NoError(t, err)
+	require.False(t, stored.IsCompleted())
+	require.Equal(t, "hash", stored.RequestHash)
+
+	key.Status = 200
+	key.Headers = []byte(`{"Etag":["\"1\""]}`)
+	key.Body = []byte(`{"deck_id":"id"}`)
+	require.NoError(t, repo.CompleteIdempotencyKey(key))
+
+	stored, err = repo.GetIdempotencyKey("", key.Key)
+	require.NoError(t, err)
+	require.True(t, stored.IsCompleted())
+	require.Equal(t, key.Body, stored.Body)
+	require.JSONEq(t, string(key.Headers), string(stored.Headers))
+
+	require.NoError(t, repo.DeleteIdempotencyKey(key))
+	_, err = repo.GetIdempotencyKey("", key.Key)
+	require.EqualError(t, err, "idempotency key not found")
+}
+
+func TestRepository_AcquireIdempotencyKey_Expired(t *testing.T) {
+	repo := newTestRepository(t)
+
+	key := &models.IdempotencyKey{
+		Key:         uuid.NewV4().String(),
+		RequestHash: "hash",
+	}
+
+	acquired, err := repo.AcquireIdempotencyKey(key, -time.Second, time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	acquired, err = repo.AcquireIdempotencyKey(key, time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+}
+
+func TestRepository_AcquireIdempotencyKey_Abandoned(t *testing.T) {
+	repo := newTestRepository(t)
+
+	key := &models.IdempotencyKey{
+		Key:         uuid.NewV4().String(),
+		RequestHash: "hash",
+	}
+
+	acquired, err := repo.AcquireIdempotencyKey(key, time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// in progress key older than lease is taken over
+	acquired, err = repo.AcquireIdempotencyKey(key, time.Hour, -time.Second)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// completed key is kept till it expires
+	key.Status = 200
+	require.NoError(t, repo.CompleteIdempotencyKey(key))
+	acquired, err = repo.AcquireIdempotencyKey(key, time.Hour, -time.Second)
+	require.NoError(t, err)
+	require.False(t, acquired)
+}
+
+func TestRepository_IdempotencyKey_Owner(t *testing.T) {
+	repo := newTestRepository(t)
+
+	owner := &models.IdempotencyKey{
+		Key:         uuid.NewV4().String(),
+		RequestHash: "hash",
+	}
+	acquired, err := repo.AcquireIdempotencyKey(owner, time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// abandoned key is taken over, previous owner can neither complete nor release it
+	taken := &models.IdempotencyKey{Key: owner.Key, RequestHash: "hash"}
+	acquired, err = repo.AcquireIdempotencyKey(taken, time.Hour, -time.Second)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	owner.Status = 200
+	require.EqualError(t, repo.CompleteIdempotencyKey(owner), "idempotency key not found")
+	require.NoError(t, repo.DeleteIdempotencyKey(owner))
+
+	stored, err := repo.GetIdempotencyKey("", owner.Key)
+	require.NoError(t, err)
+	require.False(t, stored.IsCompleted())
+	require.True(t, taken.CreatedAt.Equal(stored.CreatedAt))
+
+	taken.Status = 201
+	require.NoError(t, repo.CompleteIdempotencyKey(taken))
+}
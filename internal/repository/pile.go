@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/pkg/errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const pilesTable = "piles"
+
+var pileColumns = []string{
+	"deck_id",
+	"name",
+	"remaining",
+	"card_codes",
+	"created_at",
+	"updated_at",
+}
+
+// SavePile creates new pile or updates cards of existing one
+func (r *Repository) SavePile(pile *models.Pile) error {
+	if r.event != nil {
+		before, err := r.pileCodes(pile.DeckID, pile.Name)
+		if err != nil {
+			return err
+		}
+		r.event.RecordPile(pile.Name, before, pile.CardCodes)
+	}
+
+	now := time.Now().UTC()
+	if pile.CreatedAt.IsZero() {
+		pile.CreatedAt = now
+	}
+	pile.UpdatedAt = now
+	pile.Remaining = uint(len(pile.CardCodes))
+
+	_, err := sb.Insert(pilesTable).
+		SetMap(map[string]interface{}{
+			"deck_id":    pile.DeckID,
+			"name":       pile.Name,
+			"remaining":  pile.Remaining,
+			"card_codes": pile.CardCodes,
+			"created_at": pile.CreatedAt,
+			"updated_at": pile.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (deck_id, name) DO UPDATE SET remaining = EXCLUDED.remaining, card_codes = EXCLUDED.card_codes, updated_at = EXCLUDED.updated_at").
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPile returns pile by deck ID and pile name
+func (r *Repository) GetPile(deckID, name string) (*models.Pile, error) {
+	query, args, err := sb.Select(pileColumns...).
+		From(pilesTable).
+		Where(sq.Eq{"deck_id": deckID, "name": name}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var pile models.Pile
+	if err = sqlx.Get(r.db, &pile, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.NotFound, "pile not found")
+		}
+		return nil, err
+	}
+
+	return &pile, nil
+}
+
+// pileCodes returns cards of pile, nil is returned if pile doesn't exist
+func (r *Repository) pileCodes(deckID, name string) ([]string, error) {
+	query, args, err := sb.Select("card_codes").
+		From(pilesTable).
+		Where(sq.Eq{"deck_id": deckID, "name": name}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var codes pq.StringArray
+	if err = sqlx.Get(r.db, &codes, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// GetPilesByDeckID returns all piles attached to deck
+func (r *Repository) GetPilesByDeckID(deckID string) ([]*models.Pile, error) {
+	query, args, err := sb.Select(pileColumns...).
+		From(pilesTable).
+		Where(sq.Eq{"deck_id": deckID}).
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var piles []*models.Pile
+	if err = sqlx.Select(r.db, &piles, query, args...); err != nil {
+		return nil, err
+	}
+
+	return piles, nil
+}
+
+// DeletePilesByDeckID removes all piles attached to deck
+func (r *Repository) DeletePilesByDeckID(deckID string) error {
+	if r.event != nil {
+		r.event.ClearAllPiles()
+	}
+
+	_, err := sb.Delete(pilesTable).
+		Where(sq.Eq{"deck_id": deckID}).
+		RunWith(r.db).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/card-deck/internal/models"
+	"github.com/card-deck/pkg/errors"
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+)
+
+const templatesTable = "deck_templates"
+
+// Row locks of templates
+const (
+	lockNone      = ""
+	lockForUpdate = "FOR UPDATE"
+	lockForShare  = "FOR SHARE"
+)
+
+var templateColumns = []string{
+	"template_id",
+	"name",
+	"cards",
+	"created_at",
+	"updated_at",
+}
+
+// CreateTemplate creates new deck template
+func (r *Repository) CreateTemplate(template *models.Template) error {
+	now := time.Now().UTC()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	template.TemplateID = uuid.NewV4().String()
+
+	return r.InTx(func(tx *Repository) error {
+		_, err := sb.Insert(templatesTable).
+			SetMap(map[string]interface{}{
+				"template_id": template.TemplateID,
+				"name":        template.Name,
+				"cards":       template.Cards,
+				"created_at":  template.CreatedAt,
+				"updated_at":  template.UpdatedAt,
+			}).
+			RunWith(tx.db).
+			Exec()
+		return err
+	})
+}
+
+// GetTemplateByID returns deck template by it's ID
+func (r *Repository) GetTemplateByID(templateID string) (*models.Template, error) {
+	return r.getTemplate(templateID, lockNone)
+}
+
+// GetTemplates returns all deck templates ordered by creation time
+func (r *Repository) GetTemplates() ([]*models.Template, error) {
+	query, args, err := sb.Select(templateColumns...).
+		From(templatesTable).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*models.Template
+	if err = sqlx.Select(r.db, &templates, query, args...); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// UpdateTemplate updates name and cards of deck template,
+// template which is used by decks cannot be changed
+func (r *Repository) UpdateTemplate(template *models.Template) error {
+	return r.InTx(func(tx *Repository) error {
+		stored, err := tx.getTemplate(template.TemplateID, lockForUpdate)
+		if err != nil {
+			return err
+		}
+		if err = tx.checkTemplateNotUsed(template.TemplateID); err != nil {
+			return err
+		}
+
+		template.CreatedAt = stored.CreatedAt
+		template.UpdatedAt = time.Now().UTC()
+
+		_, err = sb.Update(templatesTable).
+			Where(sq.Eq{"template_id": template.TemplateID}).
+			SetMap(map[string]interface{}{
+				"name":       template.Name,
+				"cards":      template.Cards,
+				"updated_at": template.UpdatedAt,
+			}).
+			RunWith(tx.db).
+			Exec()
+		return err
+	})
+}
+
+// DeleteTemplate removes deck template, template which is used by decks cannot be removed
+func (r *Repository) DeleteTemplate(templateID string) error {
+	return r.InTx(func(tx *Repository) error {
+		if _, err := tx.getTemplate(templateID, lockForUpdate); err != nil {
+			return err
+		}
+		if err := tx.checkTemplateNotUsed(templateID); err != nil {
+			return err
+		}
+
+		_, err := sb.Delete(templatesTable).
+			Where(sq.Eq{"template_id": templateID}).
+			RunWith(tx.db).
+			Exec()
+		return err
+	})
+}
+
+// getTemplate returns deck template by it's ID, row is locked till the end
+// of transaction by given lock unless it is lockNone
+func (r *Repository) getTemplate(templateID string, lock string) (*models.Template, error) {
+	if _, err := uuid.FromString(templateID); err != nil {
+		return nil, errors.New(errors.NotFound, "template not found")
+	}
+
+	builder := sb.Select(templateColumns...).
+		From(templatesTable).
+		Where(sq.Eq{"template_id": templateID})
+	if lock != lockNone {
+		builder = builder.Suffix(lock)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var template models.Template
+	if err = sqlx.Get(r.db, &template, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.NotFound, "template not found")
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// checkTemplateNotUsed returns conflict error if any deck is created from template
+func (r *Repository) checkTemplateNotUsed(templateID string) error {
+	query, args, err := sb.Select("1").
+		Prefix("SELECT EXISTS (").
+		From(decksTable).
+		Where(sq.Eq{"template_id": templateID}).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var used bool
+	if err = sqlx.Get(r.db, &used, query, args...); err != nil {
+		return err
+	}
+	if used {
+		return errors.New(errors.Conflict, "template is used by decks")
+	}
+
+	return nil
+}
+
+// checkTemplateCodes locks deck template for the time of transaction, so it cannot be changed
+// till the deck created from it is stored, and checks that template still defines given codes
+func (r *Repository) checkTemplateCodes(templateID string, codes []string, decksCount uint) error {
+	template, err := r.getTemplate(templateID, lockForShare)
+	if err != nil {
+		return err
+	}
+	def, err := template.Definition()
+	if err != nil {
+		return err
+	}
+	if !def.IsValidCodes(codes, decksCount) {
+		return errors.New(errors.Conflict, "template has been changed while deck was created")
+	}
+
+	return nil
+}
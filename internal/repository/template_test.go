@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/card-deck/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Template(t *testing.T) {
+	repo := newTestRepository(t)
+
+	template := &models.Template{
+		Name:  "monsters",
+		Cards: models.TemplateCards{{Code: "DR", Name: "DRAGON"}, {Code: "GO", Name: "GOBLIN"}},
+	}
+	require.NoError(t, repo.CreateTemplate(template))
+
+	stored, err := repo.GetTemplateByID(template.TemplateID)
+	require.NoError(t, err)
+	require.Equal(t, template.Cards, stored.Cards)
+
+	template.Name = "creatures"
+	require.NoError(t, repo.UpdateTemplate(template))
+
+	deck := &models.Deck{
+		Type:          models.TemplateDeckType,
+		DecksCount:    1,
+		TemplateID:    &template.TemplateID,
+		CardCodes:     []string{"DR", "GO"},
+		OriginalCodes: []string{"DR", "GO"},
+	}
+	require.NoError(t, repo.CreateDeck(deck))
+
+	require.EqualError(t, repo.UpdateTemplate(template), "template is used by decks")
+	require.EqualError(t, repo.DeleteTemplate(template.TemplateID), "template is used by decks")
+
+	_, err = repo.GetTemplateByID("not-uuid")
+	require.EqualError(t, err, "template not found")
+}
+
+func TestRepository_CreateDeck_ChangedTemplate(t *testing.T) {
+	repo := newTestRepository(t)
+
+	template := &models.Template{
+		Name:  "monsters",
+		Cards: models.TemplateCards{{Code: "DR", Name: "DRAGON"}, {Code: "GO", Name: "GOBLIN"}},
+	}
+	require.NoError(t, repo.CreateTemplate(template))
+
+	// template is changed after deck codes are built from it
+	template.Cards = models.TemplateCards{{Code: "OR", Name: "ORC"}}
+	require.NoError(t, repo.UpdateTemplate(template))
+
+	deck := &models.Deck{
+		Type:          models.TemplateDeckType,
+		DecksCount:    1,
+		TemplateID:    &template.TemplateID,
+		CardCodes:     []string{"DR", "GO"},
+		OriginalCodes: []string{"DR", "GO"},
+	}
+	require.EqualError(t, repo.CreateDeck(deck), "template has been changed while deck was created")
+
+	deck.CardCodes = []string{"OR"}
+	deck.OriginalCodes = []string{"OR"}
+	require.NoError(t, repo.CreateDeck(deck))
+}
@@ -0,0 +1,150 @@
+package deckhelper
+
+import "strconv"
+
+// Names of built-in deck definitions
+const (
+	PiquetDeckType   = "piquet"
+	EuchreDeckType   = "euchre"
+	PinochleDeckType = "pinochle"
+	Spanish40Type    = "spanish40"
+	Spanish48Type    = "spanish48"
+	TarotDeckType    = "tarot"
+)
+
+// Constants for Spanish deck
+const (
+	OROS    = "O"
+	COPAS   = "C"
+	ESPADAS = "E"
+	BASTOS  = "B"
+
+	AS      = "1"
+	SOTA    = "10"
+	CABALLO = "11"
+	REY     = "12"
+)
+
+// Constants for tarot deck
+const (
+	KNIGHT = "C"
+	TRUMPS = "T"
+	// EXCUSE is a code of the Fool card
+	EXCUSE = "EX"
+)
+
+// Built-in deck definitions
+var (
+	// Standard is French 52-card deck
+	Standard = &DeckDefinition{
+		Name:      StandardDeckType,
+		Ranks:     CardsSequence,
+		Suits:     SuitsSequence,
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+		Ace:       ACE,
+	}
+
+	// Piquet is 32-card deck from seven to ace
+	Piquet = &DeckDefinition{
+		Name:      PiquetDeckType,
+		Ranks:     []string{SEVEN, EIGHT, NINE, TEN, JACK, QUEEN, KING, ACE},
+		Suits:     SuitsSequence,
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+		Ace:       ACE,
+	}
+
+	// Euchre is 24-card deck from nine to ace
+	Euchre = &DeckDefinition{
+		Name:      EuchreDeckType,
+		Ranks:     []string{NINE, TEN, JACK, QUEEN, KING, ACE},
+		Suits:     SuitsSequence,
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+		Ace:       ACE,
+	}
+
+	// Pinochle is 48-card deck with two copies of each card from nine to ace
+	Pinochle = &DeckDefinition{
+		Name:      PinochleDeckType,
+		Ranks:     []string{NINE, TEN, JACK, QUEEN, KING, ACE},
+		Suits:     SuitsSequence,
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+		Ace:       ACE,
+		Copies:    2,
+	}
+
+	// Spanish40 is 40-card Spanish deck without eights and nines
+	Spanish40 = &DeckDefinition{
+		Name:      Spanish40Type,
+		Ranks:     []string{AS, "2", "3", "4", "5", "6", "7", SOTA, CABALLO, REY},
+		Suits:     spanishSuits,
+		RankNames: spanishRankNames,
+		SuitNames: spanishSuitNames,
+		Ace:       AS,
+	}
+
+	// Spanish48 is 48-card Spanish deck
+	Spanish48 = &DeckDefinition{
+		Name:      Spanish48Type,
+		Ranks:     []string{AS, "2", "3", "4", "5", "6", "7", "8", "9", SOTA, CABALLO, REY},
+		Suits:     spanishSuits,
+		RankNames: spanishRankNames,
+		SuitNames: spanishSuitNames,
+		Ace:       AS,
+	}
+
+	// Tarot is 78-card French tarot deck: 56 suit cards,
+	// 21 trumps (1T-21T) and the Excuse
+	Tarot = &DeckDefinition{
+		Name:  TarotDeckType,
+		Ranks: []string{ACE, TWO, THREE, FOUR, FIVE, SIX, SEVEN, EIGHT, NINE, TEN, JACK, KNIGHT, QUEEN, KING},
+		Suits: SuitsSequence,
+		RankNames: map[string]string{
+			ACE: "ACE", TWO: TWO, THREE: THREE, FOUR: FOUR, FIVE: FIVE, SIX: SIX, SEVEN: SEVEN,
+			EIGHT: EIGHT, NINE: NINE, TEN: TEN, JACK: "JACK", KNIGHT: "KNIGHT", QUEEN: "QUEEN", KING: "KING",
+		},
+		SuitNames: SuitsMapping,
+		Ace:       ACE,
+		Extras:    tarotExtras(),
+	}
+)
+
+var (
+	spanishSuits     = []string{OROS, COPAS, ESPADAS, BASTOS}
+	spanishSuitNames = map[string]string{
+		OROS:    "OROS",
+		COPAS:   "COPAS",
+		ESPADAS: "ESPADAS",
+		BASTOS:  "BASTOS",
+	}
+	spanishRankNames = map[string]string{
+		AS: "AS", "2": "2", "3": "3", "4": "4", "5": "5", "6": "6", "7": "7", "8": "8", "9": "9",
+		SOTA:    "SOTA",
+		CABALLO: "CABALLO",
+		REY:     "REY",
+	}
+)
+
+func init() {
+	for _, def := range []*DeckDefinition{Standard, Piquet, Euchre, Pinochle, Spanish40, Spanish48, Tarot} {
+		MustRegister(def)
+	}
+}
+
+func tarotExtras() []CardDefinition {
+	extras := make([]CardDefinition, 0, 22)
+	for i := 1; i <= 21; i++ {
+		extras = append(extras, CardDefinition{
+			Code:  strconv.Itoa(i) + TRUMPS,
+			Value: strconv.Itoa(i),
+			Suit:  "TRUMPS",
+		})
+	}
+	return append(extras, CardDefinition{
+		Code:  EXCUSE,
+		Value: "EXCUSE",
+	})
+}
@@ -0,0 +1,186 @@
+package deckhelper
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Rank represents card rank code, e.g. ACE or TEN
+type Rank string
+
+// String returns rank code
+func (r Rank) String() string {
+	return string(r)
+}
+
+// Name returns name of standard deck rank, empty name is returned for unknown rank
+func (r Rank) Name() string {
+	if IsJoker(string(r)) {
+		return JokerValue
+	}
+	return CardsMapping[string(r)]
+}
+
+// Suit represents card suit code, e.g. SPADES
+type Suit string
+
+// String returns suit code
+func (s Suit) String() string {
+	return string(s)
+}
+
+// Name returns name of standard deck suit, empty name is returned for unknown suit
+func (s Suit) Name() string {
+	return SuitsMapping[string(s)]
+}
+
+// Card represents card as pair of rank and suit.
+// Jokers and extra cards of deck definitions have no suit, their rank is the whole card code.
+// Card is encoded as its code of any deck, but it is decoded as card of standard deck only,
+// use DeckCard to decode cards of other deck definitions
+type Card struct {
+	Rank Rank
+	Suit Suit
+}
+
+// ParseCard parses card code of standard deck, jokers are accepted too
+func ParseCard(code string) (Card, error) {
+	return Standard.ParseCard(code)
+}
+
+// MustParseCard is like ParseCard but panics if code cannot be parsed
+func MustParseCard(code string) Card {
+	card, err := ParseCard(code)
+	if err != nil {
+		panic(err)
+	}
+	return card
+}
+
+// ParseCards parses list of standard deck card codes
+func ParseCards(codes []string) ([]Card, error) {
+	return Standard.ParseCards(codes)
+}
+
+// ParseCard parses card code of deck definition, jokers are accepted too
+func (d *DeckDefinition) ParseCard(code string) (Card, error) {
+	if IsJoker(code) {
+		return Card{Rank: Rank(code)}, nil
+	}
+	for _, suit := range d.Suits {
+		if rank := strings.TrimSuffix(code, suit); rank != code && contains(d.Ranks, rank) {
+			return Card{Rank: Rank(rank), Suit: Suit(suit)}, nil
+		}
+	}
+	for _, extra := range d.Extras {
+		if extra.Code == code {
+			return Card{Rank: Rank(code)}, nil
+		}
+	}
+
+	return Card{}, fmt.Errorf("invalid card code %q", code)
+}
+
+// ParseCards parses list of card codes of deck definition
+func (d *DeckDefinition) ParseCards(codes []string) ([]Card, error) {
+	cards := make([]Card, 0, len(codes))
+	for _, code := range codes {
+		card, err := d.ParseCard(code)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// CardCodes returns codes of given cards
+func CardCodes(cards []Card) []string {
+	codes := make([]string, 0, len(cards))
+	for _, card := range cards {
+		codes = append(codes, card.String())
+	}
+	return codes
+}
+
+// String returns card code
+func (c Card) String() string {
+	return string(c.Rank) + string(c.Suit)
+}
+
+// IsJoker checks if card is a joker
+func (c Card) IsJoker() bool {
+	return c.Suit == "" && IsJoker(string(c.Rank))
+}
+
+// MarshalText implements encoding.TextMarshaler, card is encoded as its code
+func (c Card) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, standard deck code is expected
+// and codes of other deck definitions are rejected, see DeckCard
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// Value implements driver.Valuer, card is stored as its code
+func (c Card) Value() (driver.Value, error) {
+	return c.String(), nil
+}
+
+// Scan implements sql.Scanner
+func (c *Card) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(v))
+	case []byte:
+		return c.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into card", src)
+	}
+}
+
+// DeckCard is a card decoded by its deck definition, e.g.
+//
+//	card := DeckCard{Definition: Tarot}
+//	err := json.Unmarshal([]byte(`"21T"`), &card)
+//
+// Definition should be set before decoding, standard deck is used if it is nil.
+// DeckCard is encoded the same way as Card
+type DeckCard struct {
+	Card
+	Definition *DeckDefinition
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, code of card definition is expected
+func (c *DeckCard) UnmarshalText(text []byte) error {
+	def := c.Definition
+	if def == nil {
+		def = Standard
+	}
+	card, err := def.ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	c.Card = card
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (c *DeckCard) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(v))
+	case []byte:
+		return c.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into card", src)
+	}
+}
@@ -0,0 +1,127 @@
+package deckhelper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCard(t *testing.T) {
+	card, err := ParseCard("10H")
+	require.NoError(t, err)
+	require.Equal(t, Card{Rank: TEN, Suit: HEARTS}, card)
+	require.Equal(t, "10H", card.String())
+	require.Equal(t, "10", card.Rank.Name())
+	require.Equal(t, "HEARTS", card.Suit.Name())
+	require.False(t, card.IsJoker())
+
+	card, err = ParseCard("X2")
+	require.NoError(t, err)
+	require.Equal(t, Card{Rank: "X2"}, card)
+	require.True(t, card.IsJoker())
+	require.Equal(t, JokerValue, card.Rank.Name())
+
+	for _, code := range []string{"", "1H", "AX", "ah", "X5", "10"} {
+		_, err = ParseCard(code)
+		require.EqualError(t, err, `invalid card code "`+code+`"`)
+	}
+
+	require.Equal(t, Card{Rank: QUEEN, Suit: SPADES}, MustParseCard("QS"))
+	require.Panics(t, func() { MustParseCard("QQ") })
+}
+
+func TestDeckDefinition_ParseCard(t *testing.T) {
+	card, err := Spanish40.ParseCard("12O")
+	require.NoError(t, err)
+	require.Equal(t, Card{Rank: REY, Suit: OROS}, card)
+
+	card, err = Tarot.ParseCard("21T")
+	require.NoError(t, err)
+	require.Equal(t, Card{Rank: "21T"}, card)
+
+	_, err = Spanish40.ParseCard("8O")
+	require.Error(t, err)
+}
+
+func TestParseCards(t *testing.T) {
+	cards, err := ParseCards([]string{"AS", "X1"})
+	require.NoError(t, err)
+	require.Equal(t, []Card{{Rank: ACE, Suit: SPADES}, {Rank: "X1"}}, cards)
+	require.Equal(t, []string{"AS", "X1"}, CardCodes(cards))
+
+	_, err = ParseCards([]string{"AS", "S"})
+	require.Error(t, err)
+}
+
+func TestCard_JSON(t *testing.T) {
+	data, err := json.Marshal([]Card{MustParseCard("AS"), MustParseCard("X1")})
+	require.NoError(t, err)
+	require.JSONEq(t, `["AS", "X1"]`, string(data))
+
+	var cards []Card
+	require.NoError(t, json.Unmarshal([]byte(`["KD", "9C"]`), &cards))
+	require.Equal(t, []Card{{Rank: KING, Suit: DIAMONDS}, {Rank: NINE, Suit: CLUBS}}, cards)
+
+	require.Error(t, json.Unmarshal([]byte(`["KK"]`), &cards))
+}
+
+func TestCard_SQL(t *testing.T) {
+	value, err := MustParseCard("JC").Value()
+	require.NoError(t, err)
+	require.Equal(t, "JC", value)
+
+	var card Card
+	require.NoError(t, card.Scan("7D"))
+	require.Equal(t, Card{Rank: SEVEN, Suit: DIAMONDS}, card)
+	require.NoError(t, card.Scan([]byte("X3")))
+	require.Equal(t, Card{Rank: "X3"}, card)
+	require.Error(t, card.Scan(7))
+	require.Error(t, card.Scan("7"))
+}
+
+func TestCard_StandardOnly(t *testing.T) {
+	tarot, err := Tarot.ParseCard("21T")
+	require.NoError(t, err)
+	spanish, err := Spanish40.ParseCard("12O")
+	require.NoError(t, err)
+
+	data, err := json.Marshal([]Card{tarot, spanish})
+	require.NoError(t, err)
+	require.JSONEq(t, `["21T", "12O"]`, string(data))
+
+	// plain card is decoded as card of standard deck only
+	var card Card
+	require.EqualError(t, json.Unmarshal([]byte(`"21T"`), &card), `invalid card code "21T"`)
+	require.EqualError(t, card.Scan("12O"), `invalid card code "12O"`)
+}
+
+func TestDeckCard(t *testing.T) {
+	for _, def := range []*DeckDefinition{Tarot, Spanish40, Standard} {
+		for _, code := range def.Codes() {
+			card, err := def.ParseCard(code)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(DeckCard{Card: card, Definition: def})
+			require.NoError(t, err)
+			require.Equal(t, `"`+code+`"`, string(data))
+
+			decoded := DeckCard{Definition: def}
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			require.Equal(t, card, decoded.Card)
+
+			value, err := decoded.Value()
+			require.NoError(t, err)
+			scanned := DeckCard{Definition: def}
+			require.NoError(t, scanned.Scan(value))
+			require.Equal(t, card, scanned.Card)
+		}
+	}
+
+	// standard deck is used without definition
+	var card DeckCard
+	require.NoError(t, card.UnmarshalText([]byte("AS")))
+	require.Equal(t, Card{Rank: ACE, Suit: SPADES}, card.Card)
+	require.Error(t, card.UnmarshalText([]byte("21T")))
+	require.Error(t, card.Scan(1))
+}
@@ -0,0 +1,135 @@
+package deckhelper
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BridgeSuits is a bridge order of suits from the lowest to the highest
+var BridgeSuits = []Suit{CLUBS, DIAMONDS, HEARTS, SPADES}
+
+// Ordering describes rules of comparing cards. Cards are compared by trump first,
+// then by rank and then by suit. Jokers are higher than any other card,
+// unknown ranks and suits are lower than known ones
+type Ordering struct {
+	// Ranks lists ranks from the lowest to the highest
+	Ranks []Rank
+	// Suits lists suits from the lowest to the highest,
+	// cards of the same rank are equal if it's empty
+	Suits []Suit
+	// Trump is a suit higher than all other suits, empty suit means no trump
+	Trump Suit
+}
+
+// Ordering returns ordering of deck definition ranks as they are declared followed by extra cards,
+// ace rank of definition is moved to the highest rank if aceHigh is set and to the lowest one otherwise.
+// Suits precedence is not set
+func (d *DeckDefinition) Ordering(aceHigh bool) *Ordering {
+	ranks := make([]Rank, 0, len(d.Ranks)+len(d.Extras))
+	if d.Ace != "" && !aceHigh {
+		ranks = append(ranks, Rank(d.Ace))
+	}
+	for _, rank := range d.Ranks {
+		if rank != d.Ace {
+			ranks = append(ranks, Rank(rank))
+		}
+	}
+	if d.Ace != "" && aceHigh {
+		ranks = append(ranks, Rank(d.Ace))
+	}
+	for _, extra := range d.Extras {
+		ranks = append(ranks, Rank(extra.Code))
+	}
+
+	return &Ordering{Ranks: ranks}
+}
+
+// WithSuits returns copy of ordering with given suits precedence, from the lowest to the highest
+func (o Ordering) WithSuits(suits []Suit) *Ordering {
+	o.Suits = suits
+	return &o
+}
+
+// WithTrump returns copy of ordering with given trump suit
+func (o Ordering) WithTrump(trump Suit) *Ordering {
+	o.Trump = trump
+	return &o
+}
+
+// Compare returns negative number if card a is lower than b, positive if higher and 0 if they are equal
+func (o *Ordering) Compare(a, b Card) int {
+	if a.IsJoker() || b.IsJoker() {
+		return o.rankIndex(a) - o.rankIndex(b)
+	}
+	if o.Trump != "" {
+		if aTrump, bTrump := a.Suit == o.Trump, b.Suit == o.Trump; aTrump != bTrump {
+			if aTrump {
+				return 1
+			}
+			return -1
+		}
+	}
+	if diff := o.rankIndex(a) - o.rankIndex(b); diff != 0 {
+		return diff
+	}
+	return o.suitIndex(a.Suit) - o.suitIndex(b.Suit)
+}
+
+// Less reports whether card a is lower than b
+func (o *Ordering) Less(a, b Card) bool {
+	return o.Compare(a, b) < 0
+}
+
+// Sort sorts cards from the lowest to the highest, equal cards keep their order
+func (o *Ordering) Sort(cards []Card) {
+	sort.SliceStable(cards, func(i, j int) bool {
+		return o.Less(cards[i], cards[j])
+	})
+}
+
+// SortCodes sorts card codes of deck definition from the lowest to the highest
+func (o *Ordering) SortCodes(def *DeckDefinition, codes []string) ([]string, error) {
+	cards, err := def.ParseCards(codes)
+	if err != nil {
+		return nil, err
+	}
+	o.Sort(cards)
+
+	return CardCodes(cards), nil
+}
+
+// Validate checks if ordering suits are suits of deck definition
+func (o *Ordering) Validate(def *DeckDefinition) error {
+	for _, suit := range o.Suits {
+		if !contains(def.Suits, string(suit)) {
+			return fmt.Errorf("unknown suit %q", suit)
+		}
+	}
+	if o.Trump != "" && !contains(def.Suits, string(o.Trump)) {
+		return fmt.Errorf("unknown trump suit %q", o.Trump)
+	}
+	return nil
+}
+
+func (o *Ordering) rankIndex(card Card) int {
+	for i, joker := range CreateJokerCodes(MaxJokers) {
+		if card.IsJoker() && joker == string(card.Rank) {
+			return len(o.Ranks) + i
+		}
+	}
+	for i, rank := range o.Ranks {
+		if rank == card.Rank {
+			return i
+		}
+	}
+	return -1
+}
+
+func (o *Ordering) suitIndex(suit Suit) int {
+	for i, s := range o.Suits {
+		if s == suit {
+			return i
+		}
+	}
+	return -1
+}
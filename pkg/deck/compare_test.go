@@ -0,0 +1,76 @@
+package deckhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrdering_Compare(t *testing.T) {
+	aceHigh := Standard.Ordering(true)
+	aceLow := Standard.Ordering(false)
+
+	require.True(t, aceHigh.Less(MustParseCard("KS"), MustParseCard("AS")))
+	require.True(t, aceLow.Less(MustParseCard("AS"), MustParseCard("2S")))
+	require.True(t, aceLow.Less(MustParseCard("KS"), MustParseCard("X1")))
+	require.True(t, aceLow.Less(MustParseCard("X1"), MustParseCard("X2")))
+
+	// suits are equal without precedence
+	require.Equal(t, 0, aceHigh.Compare(MustParseCard("AS"), MustParseCard("AC")))
+
+	bridge := aceHigh.WithSuits(BridgeSuits)
+	require.True(t, bridge.Less(MustParseCard("AC"), MustParseCard("AS")))
+	require.True(t, bridge.Less(MustParseCard("AS"), MustParseCard("X1")))
+
+	trump := bridge.WithTrump(CLUBS)
+	require.True(t, trump.Less(MustParseCard("AS"), MustParseCard("2C")))
+	require.True(t, trump.Less(MustParseCard("2C"), MustParseCard("3C")))
+	require.Empty(t, bridge.Trump)
+}
+
+func TestOrdering_SortCodes(t *testing.T) {
+	ordering := Standard.Ordering(true).WithSuits(BridgeSuits).WithTrump(DIAMONDS)
+	codes, err := ordering.SortCodes(Standard, []string{"X1", "AS", "2D", "AC", "10H", "KD"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10H", "AC", "AS", "2D", "KD", "X1"}, codes)
+
+	_, err = ordering.SortCodes(Standard, []string{"AS", "1S"})
+	require.Error(t, err)
+
+	codes, err = Tarot.Ordering(false).SortCodes(Tarot, []string{"EX", "21T", "KS", "1T"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"KS", "1T", "21T", "EX"}, codes)
+}
+
+func TestOrdering_SortCodes_Ace(t *testing.T) {
+	cases := []struct {
+		def     *DeckDefinition
+		codes   []string
+		aceHigh []string
+		aceLow  []string
+	}{
+		{Piquet, []string{"KS", "AS", "7S"}, []string{"7S", "KS", "AS"}, []string{"AS", "7S", "KS"}},
+		{Euchre, []string{"AS", "9S", "KS"}, []string{"9S", "KS", "AS"}, []string{"AS", "9S", "KS"}},
+		{Pinochle, []string{"AS", "9S", "AS"}, []string{"9S", "AS", "AS"}, []string{"AS", "AS", "9S"}},
+		{Spanish40, []string{"12O", "1O", "7O"}, []string{"7O", "12O", "1O"}, []string{"1O", "7O", "12O"}},
+		{Spanish48, []string{"9O", "1O", "12O"}, []string{"9O", "12O", "1O"}, []string{"1O", "9O", "12O"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.def.Name, func(t *testing.T) {
+			codes, err := c.def.Ordering(true).SortCodes(c.def, c.codes)
+			require.NoError(t, err)
+			require.Equal(t, c.aceHigh, codes)
+
+			codes, err = c.def.Ordering(false).SortCodes(c.def, c.codes)
+			require.NoError(t, err)
+			require.Equal(t, c.aceLow, codes)
+		})
+	}
+}
+
+func TestOrdering_Validate(t *testing.T) {
+	require.NoError(t, Standard.Ordering(true).WithSuits(BridgeSuits).WithTrump(HEARTS).Validate(Standard))
+	require.EqualError(t, Standard.Ordering(true).WithSuits([]Suit{OROS}).Validate(Standard), `unknown suit "O"`)
+	require.EqualError(t, Standard.Ordering(true).WithTrump(OROS).Validate(Standard), `unknown trump suit "O"`)
+}
@@ -1,10 +1,6 @@
 package deckhelper
 
-import (
-	"fmt"
-	"math/rand"
-	"time"
-)
+import "fmt"
 
 // Constants for standard 52-card deck
 const (
@@ -26,6 +22,15 @@ const (
 	DIAMONDS = "D"
 	HEARTS   = "H"
 	SPADES   = "S"
+
+	// JOKER is a prefix of joker codes, jokers are numbered like X1, X2
+	JOKER = "X"
+	// JokerValue is a value of joker cards, jokers have no suit
+	JokerValue = "JOKER"
+	// MaxJokers is a maximum count of jokers in deck
+	MaxJokers = 4
+	// MaxDecksCount is a maximum count of decks combined into one shoe
+	MaxDecksCount = 8
 )
 
 // Default sequences of cards
@@ -70,32 +75,40 @@ func CreateDefaultCodes() (codes []string) {
 	return codes
 }
 
-// ShuffleDeck returns shuffled deck codes
+// CreateJokerCodes creates codes for given count of jokers
+func CreateJokerCodes(count uint) (codes []string) {
+	for i := uint(1); i <= count; i++ {
+		codes = append(codes, fmt.Sprintf("%s%d", JOKER, i))
+	}
+	return codes
+}
+
+// IsJoker checks if given code is a joker code
+func IsJoker(code string) bool {
+	return contains(CreateJokerCodes(MaxJokers), code)
+}
+
+// ShuffleDeck shuffles deck codes in place, use Shuffle with seeded random for reproducible result
 func ShuffleDeck(codes []string) {
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(codes), func(i, j int) { codes[i], codes[j] = codes[j], codes[i] })
+	Shuffle(codes, NewRandom())
 }
 
 // IsValidCodes checks if given list of codes is valid
-// by comparing with default codes and check if duplicates presented
+// by comparing with default codes and jokers and check if duplicates presented
 func IsValidCodes(codes []string) bool {
-	defaultCodes := CreateDefaultCodes()
-	for _, code := range codes {
-		if !contains(defaultCodes, code) {
-			return false
-		}
-	}
-
-	keys := make(map[string]bool)
-	for _, code := range codes {
-		if _, value := keys[code]; value {
-			return false
-		}
-		keys[code] = true
+	return IsValidCodesForDecks(codes, 1)
+}
 
-	}
+// IsValidCodesForDecks checks if given list of codes is valid for shoe
+// of given count of standard decks, each code can be presented at most decksCount times
+func IsValidCodesForDecks(codes []string, decksCount uint) bool {
+	return Standard.IsValidCodes(codes, decksCount)
+}
 
-	return true
+// CreateShoeCodes creates cards sequence of given count of standard decks,
+// each deck is default one followed by given count of jokers
+func CreateShoeCodes(decksCount, jokers uint) []string {
+	return Standard.ShoeCodes(decksCount, jokers)
 }
 
 func contains(s []string, str string) bool {
@@ -25,4 +25,44 @@ func TestIsValidCodes(t *testing.T) {
 
 	isValid = IsValidCodes([]string{"AC", "AC", "2C"})
 	require.Equal(t, false, isValid)
+
+	isValid = IsValidCodes([]string{"AC", "X1", "X4"})
+	require.Equal(t, true, isValid)
+
+	isValid = IsValidCodes([]string{"X1", "X1"})
+	require.Equal(t, false, isValid)
+
+	isValid = IsValidCodes([]string{"X5"})
+	require.Equal(t, false, isValid)
+}
+
+func TestCreateJokerCodes(t *testing.T) {
+	require.Empty(t, CreateJokerCodes(0))
+	require.Equal(t, []string{"X1", "X2"}, CreateJokerCodes(2))
+}
+
+func TestIsJoker(t *testing.T) {
+	require.True(t, IsJoker("X1"))
+	require.True(t, IsJoker("X4"))
+	require.False(t, IsJoker("X5"))
+	require.False(t, IsJoker("10S"))
+}
+
+func TestIsValidCodesForDecks(t *testing.T) {
+	require.True(t, IsValidCodesForDecks([]string{"AS", "AS", "X1", "X1"}, 2))
+	require.False(t, IsValidCodesForDecks([]string{"AS", "AS", "AS"}, 2))
+	require.False(t, IsValidCodesForDecks([]string{"AS", "S1"}, 2))
+	require.False(t, IsValidCodesForDecks([]string{"AS"}, 0))
+}
+
+func TestCreateShoeCodes(t *testing.T) {
+	require.Empty(t, CreateShoeCodes(0, 0))
+	require.Len(t, CreateShoeCodes(6, 0), 6*52)
+
+	codes := CreateShoeCodes(2, 1)
+	require.Len(t, codes, 2*53)
+	require.Equal(t, "X1", codes[52])
+	require.Equal(t, "X1", codes[105])
+	require.True(t, IsValidCodesForDecks(codes, 2))
+	require.False(t, IsValidCodes(codes))
 }
@@ -0,0 +1,258 @@
+package deckhelper
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MaxDeckSize is a maximum count of cards in one deck of any definition including jokers
+const MaxDeckSize = 128
+
+// StandardDeckType is a name of default French 52-card deck definition
+const StandardDeckType = "standard"
+
+// Possible orders of cards in deck definition
+const (
+	// OrderBySuits places all ranks of the first suit, then all ranks of the next one
+	OrderBySuits = "suits"
+	// OrderByRanks places all suits of the first rank, then all suits of the next one
+	OrderByRanks = "ranks"
+)
+
+// CardDefinition describes single card of deck definition
+type CardDefinition struct {
+	Code  string
+	Value string
+	Suit  string
+	// Attributes are optional custom properties of the card
+	Attributes map[string]interface{}
+}
+
+// DeckDefinition describes composition of some type of deck.
+// Deck consists of each rank of each suit (code is rank code followed by suit code)
+// and extra cards which don't fit ranks × suits, e.g. tarot trumps.
+// Jokers can be added to deck of any definition.
+type DeckDefinition struct {
+	Name string
+	// Ranks are rank codes in default deck order
+	Ranks []string
+	// Suits are suit codes in default deck order
+	Suits []string
+	// RankNames maps rank code to rank name
+	RankNames map[string]string
+	// SuitNames maps suit code to suit name
+	SuitNames map[string]string
+	// Ace is a rank code of ace which is the highest or the lowest rank
+	// depending on sort order, empty if deck has no ace
+	Ace string
+	// Extras are cards placed after ranks × suits
+	Extras []CardDefinition
+	// Copies is a count of copies of each card in one deck, e.g. 2 for pinochle,
+	// 0 is treated as 1
+	Copies uint
+	// Order is an order of ranks × suits cards, empty order is treated as OrderBySuits
+	Order string
+	// Jokers is a count of jokers added to each deck if other count is not requested
+	Jokers uint
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*DeckDefinition{}
+)
+
+// Register validates and adds deck definition to registry
+func Register(def *DeckDefinition) error {
+	if err := def.Validate(); err != nil {
+		return err
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[def.Name]; ok {
+		return fmt.Errorf("deck definition %q is already registered", def.Name)
+	}
+	registry[def.Name] = def
+
+	return nil
+}
+
+// MustRegister adds deck definition to registry and panics if it's not valid
+func MustRegister(def *DeckDefinition) {
+	if err := Register(def); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns registered deck definition by it's name,
+// empty name stands for standard deck
+func Lookup(name string) (*DeckDefinition, bool) {
+	if name == "" {
+		name = StandardDeckType
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, ok := registry[name]
+
+	return def, ok
+}
+
+// DefinitionNames returns sorted names of all registered deck definitions
+func DefinitionNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Validate checks if deck definition is complete and has no duplicated codes
+func (d *DeckDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("deck definition name is required")
+	}
+	if len(d.Ranks) == 0 && len(d.Extras) == 0 {
+		return fmt.Errorf("deck definition %q has no cards", d.Name)
+	}
+	if len(d.Ranks) > 0 && len(d.Suits) == 0 {
+		return fmt.Errorf("deck definition %q has ranks without suits", d.Name)
+	}
+	if d.Order != "" && d.Order != OrderBySuits && d.Order != OrderByRanks {
+		return fmt.Errorf("deck definition %q has unknown order %q, use %q or %q", d.Name, d.Order, OrderBySuits, OrderByRanks)
+	}
+	if d.Jokers > MaxJokers {
+		return fmt.Errorf("deck definition %q jokers cannot be more than %d", d.Name, MaxJokers)
+	}
+	for _, rank := range d.Ranks {
+		if rank == "" || d.RankNames[rank] == "" {
+			return fmt.Errorf("deck definition %q has rank %q without name", d.Name, rank)
+		}
+	}
+	if d.Ace != "" && !contains(d.Ranks, d.Ace) {
+		return fmt.Errorf("deck definition %q has unknown ace rank %q", d.Name, d.Ace)
+	}
+	for _, suit := range d.Suits {
+		if suit == "" || d.SuitNames[suit] == "" {
+			return fmt.Errorf("deck definition %q has suit %q without name", d.Name, suit)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range append(d.baseCodes(), CreateJokerCodes(MaxJokers)...) {
+		if code == "" {
+			return fmt.Errorf("deck definition %q has card without code", d.Name)
+		}
+		if seen[code] {
+			return fmt.Errorf("deck definition %q has duplicated card %q", d.Name, code)
+		}
+		seen[code] = true
+	}
+
+	if size := len(d.Codes()) + MaxJokers; size > MaxDeckSize {
+		return fmt.Errorf("deck definition %q has %d cards, maximum is %d", d.Name, size, MaxDeckSize)
+	}
+
+	return nil
+}
+
+// Codes returns codes of one deck in default order
+func (d *DeckDefinition) Codes() (codes []string) {
+	for i := uint(0); i < d.copies(); i++ {
+		codes = append(codes, d.baseCodes()...)
+	}
+	return codes
+}
+
+// ShoeCodes returns codes of given count of decks,
+// each deck is followed by given count of jokers
+func (d *DeckDefinition) ShoeCodes(decksCount, jokers uint) (codes []string) {
+	for i := uint(0); i < decksCount; i++ {
+		codes = append(codes, d.Codes()...)
+		codes = append(codes, CreateJokerCodes(jokers)...)
+	}
+	return codes
+}
+
+// Cards maps codes of all cards which can be presented in deck
+// (including jokers) to their definitions
+func (d *DeckDefinition) Cards() map[string]CardDefinition {
+	cards := make(map[string]CardDefinition)
+	for _, suit := range d.Suits {
+		for _, rank := range d.Ranks {
+			code := rank + suit
+			cards[code] = CardDefinition{
+				Code:  code,
+				Value: d.RankNames[rank],
+				Suit:  d.SuitNames[suit],
+			}
+		}
+	}
+	for _, extra := range d.Extras {
+		cards[extra.Code] = extra
+	}
+	for _, code := range CreateJokerCodes(MaxJokers) {
+		cards[code] = CardDefinition{
+			Code:  code,
+			Value: JokerValue,
+		}
+	}
+	return cards
+}
+
+// IsValidCodes checks if given list of codes is valid for shoe of given count of decks:
+// each code is known and presented not more times than decks hold it's copies
+func (d *DeckDefinition) IsValidCodes(codes []string, decksCount uint) bool {
+	cards := d.Cards()
+
+	copies := make(map[string]uint)
+	for _, code := range codes {
+		if _, ok := cards[code]; !ok {
+			return false
+		}
+
+		maxCopies := d.copies() * decksCount
+		if IsJoker(code) {
+			maxCopies = decksCount
+		}
+		copies[code]++
+		if copies[code] > maxCopies {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (d *DeckDefinition) baseCodes() (codes []string) {
+	if d.Order == OrderByRanks {
+		for _, rank := range d.Ranks {
+			for _, suit := range d.Suits {
+				codes = append(codes, rank+suit)
+			}
+		}
+	} else {
+		for _, suit := range d.Suits {
+			for _, rank := range d.Ranks {
+				codes = append(codes, rank+suit)
+			}
+		}
+	}
+	for _, extra := range d.Extras {
+		codes = append(codes, extra.Code)
+	}
+	return codes
+}
+
+func (d *DeckDefinition) copies() uint {
+	if d.Copies == 0 {
+		return 1
+	}
+	return d.Copies
+}
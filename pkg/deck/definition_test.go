@@ -0,0 +1,155 @@
+package deckhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltInDefinitions(t *testing.T) {
+	sizes := map[string]int{
+		StandardDeckType: 52,
+		PiquetDeckType:   32,
+		EuchreDeckType:   24,
+		PinochleDeckType: 48,
+		Spanish40Type:    40,
+		Spanish48Type:    48,
+		TarotDeckType:    78,
+	}
+
+	for name, size := range sizes {
+		def, ok := Lookup(name)
+		require.True(t, ok, name)
+		require.Len(t, def.Codes(), size, name)
+		require.True(t, def.IsValidCodes(def.Codes(), 1), name)
+	}
+
+	require.Equal(t, CreateDefaultCodes(), Standard.Codes())
+}
+
+func TestLookup(t *testing.T) {
+	def, ok := Lookup("")
+	require.True(t, ok)
+	require.Equal(t, Standard, def)
+
+	_, ok = Lookup("unknown")
+	require.False(t, ok)
+}
+
+func TestRegister(t *testing.T) {
+	def := &DeckDefinition{
+		Name:      "test-register",
+		Ranks:     []string{ACE, KING},
+		Suits:     []string{SPADES},
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+	}
+	require.NoError(t, Register(def))
+	require.Contains(t, DefinitionNames(), "test-register")
+
+	err := Register(def)
+	require.EqualError(t, err, `deck definition "test-register" is already registered`)
+}
+
+func TestDeckDefinition_Validate(t *testing.T) {
+	require.EqualError(t, (&DeckDefinition{}).Validate(), "deck definition name is required")
+
+	require.EqualError(t, (&DeckDefinition{Name: "empty"}).Validate(), `deck definition "empty" has no cards`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:      "no-suits",
+		Ranks:     []string{ACE},
+		RankNames: CardsMapping,
+	}).Validate(), `deck definition "no-suits" has ranks without suits`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:      "no-name",
+		Ranks:     []string{"Z"},
+		Suits:     []string{SPADES},
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+	}).Validate(), `deck definition "no-name" has rank "Z" without name`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:      "no-ace",
+		Ranks:     []string{KING},
+		Suits:     []string{SPADES},
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+		Ace:       ACE,
+	}).Validate(), `deck definition "no-ace" has unknown ace rank "A"`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:   "duplicated",
+		Extras: []CardDefinition{{Code: "X1", Value: "FAKE JOKER"}},
+	}).Validate(), `deck definition "duplicated" has duplicated card "X1"`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:      "huge",
+		Ranks:     CardsSequence,
+		Suits:     SuitsSequence,
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+		Copies:    3,
+	}).Validate(), `deck definition "huge" has 160 cards, maximum is 128`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:   "order",
+		Extras: []CardDefinition{{Code: "A", Value: "A"}},
+		Order:  "random",
+	}).Validate(), `deck definition "order" has unknown order "random", use "suits" or "ranks"`)
+
+	require.EqualError(t, (&DeckDefinition{
+		Name:   "jokers",
+		Extras: []CardDefinition{{Code: "A", Value: "A"}},
+		Jokers: 5,
+	}).Validate(), `deck definition "jokers" jokers cannot be more than 4`)
+}
+
+func TestDeckDefinition_Codes(t *testing.T) {
+	def := &DeckDefinition{
+		Name:      "order",
+		Ranks:     []string{ACE, KING},
+		Suits:     []string{SPADES, HEARTS},
+		RankNames: CardsMapping,
+		SuitNames: SuitsMapping,
+	}
+	require.Equal(t, []string{"AS", "KS", "AH", "KH"}, def.Codes())
+
+	def.Order = OrderByRanks
+	require.Equal(t, []string{"AS", "AH", "KS", "KH"}, def.Codes())
+}
+
+func TestDeckDefinition_IsValidCodes(t *testing.T) {
+	require.True(t, Pinochle.IsValidCodes([]string{"AS", "AS"}, 1))
+	require.False(t, Pinochle.IsValidCodes([]string{"AS", "AS", "AS"}, 1))
+	require.True(t, Pinochle.IsValidCodes([]string{"AS", "AS", "AS"}, 2))
+	require.False(t, Pinochle.IsValidCodes([]string{"2S"}, 1))
+	require.True(t, Pinochle.IsValidCodes([]string{"X1"}, 1))
+	require.False(t, Pinochle.IsValidCodes([]string{"X1", "X1"}, 1))
+
+	require.True(t, Tarot.IsValidCodes([]string{"21T", "EX", "CC"}, 1))
+	require.False(t, Tarot.IsValidCodes([]string{"22T"}, 1))
+
+	require.True(t, Spanish40.IsValidCodes([]string{"1O", "12B"}, 1))
+	require.False(t, Spanish40.IsValidCodes([]string{"8O"}, 1))
+	require.True(t, Spanish48.IsValidCodes([]string{"8O"}, 1))
+}
+
+func TestDeckDefinition_ShoeCodes(t *testing.T) {
+	codes := Piquet.ShoeCodes(2, 1)
+	require.Len(t, codes, 2*33)
+	require.Equal(t, "7S", codes[0])
+	require.Equal(t, "X1", codes[32])
+}
+
+func TestDeckDefinition_Cards(t *testing.T) {
+	cards := Tarot.Cards()
+	require.Equal(t, CardDefinition{Code: "CH", Value: "KNIGHT", Suit: "HEARTS"}, cards["CH"])
+	require.Equal(t, CardDefinition{Code: "7T", Value: "7", Suit: "TRUMPS"}, cards["7T"])
+	require.Equal(t, CardDefinition{Code: "EX", Value: "EXCUSE"}, cards["EX"])
+	require.Equal(t, CardDefinition{Code: "X2", Value: "JOKER"}, cards["X2"])
+
+	cards = Spanish40.Cards()
+	require.Equal(t, CardDefinition{Code: "11C", Value: "CABALLO", Suit: "COPAS"}, cards["11C"])
+}
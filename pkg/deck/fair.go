@@ -0,0 +1,88 @@
+package deckhelper
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// serverSeedSize is a count of random bytes of server seed
+const serverSeedSize = 32
+
+// NewServerSeed returns new random hex encoded server seed of provably fair shuffle
+func NewServerSeed() (string, error) {
+	buf := make([]byte, serverSeedSize)
+	if _, err := crand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// fairRandom is deterministic random generator of provably fair shuffle.
+// Numbers are taken by 8 bytes (big endian) from blocks HMAC-SHA256(server seed, "client seed:N"),
+// where N is a block number starting from 0, so it can be reproduced by any HMAC implementation
+type fairRandom struct {
+	serverSeed string
+	clientSeed string
+	block      uint64
+	buf        []byte
+}
+
+// NewFairRandom returns deterministic random generator of provably fair shuffle for given seeds
+func NewFairRandom(serverSeed, clientSeed string) Random {
+	return &fairRandom{serverSeed: serverSeed, clientSeed: clientSeed}
+}
+
+// Intn returns uniformly distributed number in [0, n)
+func (r *fairRandom) Intn(n int) int {
+	return uniform(n, r.next)
+}
+
+func (r *fairRandom) next() uint64 {
+	if len(r.buf) < 8 {
+		mac := hmac.New(sha256.New, []byte(r.serverSeed))
+		_, _ = fmt.Fprintf(mac, "%s:%d", r.clientSeed, r.block)
+		r.block++
+		r.buf = mac.Sum(nil)
+	}
+	v := binary.BigEndian.Uint64(r.buf[:8])
+	r.buf = r.buf[8:]
+	return v
+}
+
+// FairShuffle returns codes shuffled by provably fair generator of given seeds,
+// given codes are not changed
+func FairShuffle(codes []string, serverSeed, clientSeed string) []string {
+	order := append([]string(nil), codes...)
+	Shuffle(order, NewFairRandom(serverSeed, clientSeed))
+	return order
+}
+
+// Commitment returns hex encoded SHA-256 hash of server seed and shuffled order,
+// it is published before the order is revealed
+func Commitment(serverSeed string, order []string) string {
+	sum := sha256.Sum256([]byte(serverSeed + ":" + strings.Join(order, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyFairShuffle checks that revealed order matches published commitment
+// and that it is the shuffle of given codes by given seeds
+func VerifyFairShuffle(codes []string, serverSeed, clientSeed, commitment string, order []string) error {
+	if Commitment(serverSeed, order) != commitment {
+		return fmt.Errorf("order doesn't match commitment")
+	}
+	expected := FairShuffle(codes, serverSeed, clientSeed)
+	if len(expected) != len(order) {
+		return fmt.Errorf("order doesn't match seeds")
+	}
+	for i := range expected {
+		if expected[i] != order[i] {
+			return fmt.Errorf("order doesn't match seeds")
+		}
+	}
+	return nil
+}
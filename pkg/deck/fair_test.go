@@ -0,0 +1,40 @@
+package deckhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServerSeed(t *testing.T) {
+	seed, err := NewServerSeed()
+	require.NoError(t, err)
+	require.Len(t, seed, 64)
+
+	other, err := NewServerSeed()
+	require.NoError(t, err)
+	require.NotEqual(t, seed, other)
+}
+
+func TestFairShuffle(t *testing.T) {
+	codes := CreateDefaultCodes()
+	order := FairShuffle(codes, "server", "client")
+	require.Equal(t, CreateDefaultCodes(), codes)
+	require.Equal(t, order, FairShuffle(codes, "server", "client"))
+	require.NotEqual(t, order, FairShuffle(codes, "server", "other"))
+	require.ElementsMatch(t, codes, order)
+
+	// generator is fixed, so the order can be reproduced outside of this package
+	require.Equal(t, []string{"3S", "2S", "AS"}, FairShuffle([]string{"AS", "2S", "3S"}, "server", "client"))
+}
+
+func TestVerifyFairShuffle(t *testing.T) {
+	codes := CreateDefaultCodes()
+	order := FairShuffle(codes, "server", "client")
+	commitment := Commitment("server", order)
+
+	require.NoError(t, VerifyFairShuffle(codes, "server", "client", commitment, order))
+	require.EqualError(t, VerifyFairShuffle(codes, "other", "client", commitment, order), "order doesn't match commitment")
+	require.EqualError(t, VerifyFairShuffle(codes, "server", "client", commitment, codes), "order doesn't match commitment")
+	require.EqualError(t, VerifyFairShuffle(codes, "server", "other", commitment, order), "order doesn't match seeds")
+}
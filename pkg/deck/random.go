@@ -0,0 +1,108 @@
+package deckhelper
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Random generators which can be selected for deck
+const (
+	// RNGMath is math/rand generator, seeded by current time or by seed of deck
+	RNGMath = "math"
+	// RNGCrypto is cryptographically secure generator backed by crypto/rand, it cannot be seeded
+	RNGCrypto = "crypto"
+	// RNGFair is provably fair shuffle by server and client seeds (see FairShuffle),
+	// it is used by provably fair decks only and cannot be selected
+	RNGFair = "fair"
+)
+
+// IsValidRNG checks if random generator name is known and can be selected for deck
+func IsValidRNG(name string) bool {
+	return name == RNGMath || name == RNGCrypto
+}
+
+// Random is a source of random numbers used to shuffle and draw cards
+type Random interface {
+	// Intn returns uniformly distributed number in [0, n), n should be positive
+	Intn(n int) int
+}
+
+// NewRandom returns random generator seeded by current time, its sequence is not reproducible
+func NewRandom() Random {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewSeededRandom returns random generator for given step of sequence started by seed.
+// The same seed and step always give the same numbers, so operations of deck
+// can be replayed by taking the next step for each of them
+func NewSeededRandom(seed int64, step uint) Random {
+	return rand.New(rand.NewSource(mixSeed(seed, uint64(step))))
+}
+
+// Shuffle shuffles codes in place by Fisher–Yates algorithm using given random generator
+func Shuffle(codes []string, rnd Random) {
+	for i := len(codes) - 1; i > 0; i-- {
+		j := rnd.Intn(i + 1)
+		codes[i], codes[j] = codes[j], codes[i]
+	}
+}
+
+// Perm returns random permutation of numbers [0, n) using given random generator
+func Perm(n int, rnd Random) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := rnd.Intn(i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
+
+// mixSeed derives independent seed for step of sequence by splitmix64 finalizer
+func mixSeed(seed int64, step uint64) int64 {
+	z := uint64(seed) + (step+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// cryptoRandom is unbiased random generator backed by crypto/rand
+type cryptoRandom struct{}
+
+// NewCryptoRandom returns cryptographically secure random generator backed by crypto/rand,
+// it panics if system random source fails
+func NewCryptoRandom() Random {
+	return cryptoRandom{}
+}
+
+// Intn returns uniformly distributed number in [0, n)
+func (cryptoRandom) Intn(n int) int {
+	return uniform(n, func() uint64 {
+		var buf [8]byte
+		if _, err := crand.Read(buf[:]); err != nil {
+			panic(fmt.Sprintf("crypto random failed: %v", err))
+		}
+		return binary.BigEndian.Uint64(buf[:])
+	})
+}
+
+// uniform returns uniformly distributed number in [0, n) from given uint64 numbers.
+// Numbers from the incomplete last range of uint64 are rejected to avoid modulo bias
+func uniform(n int, next func() uint64) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	max := uint64(n)
+	// 2^64 mod max, numbers below it are rejected
+	min := -max % max
+	for {
+		if v := next(); v >= min {
+			return int(v % max)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package deckhelper
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShuffle(t *testing.T) {
+	first := CreateDefaultCodes()
+	Shuffle(first, NewSeededRandom(1, 0))
+	second := CreateDefaultCodes()
+	Shuffle(second, NewSeededRandom(1, 0))
+	require.Equal(t, first, second)
+	require.NotEqual(t, CreateDefaultCodes(), first)
+
+	next := CreateDefaultCodes()
+	Shuffle(next, NewSeededRandom(1, 1))
+	require.NotEqual(t, first, next)
+
+	sort.Strings(first)
+	expected := CreateDefaultCodes()
+	sort.Strings(expected)
+	require.Equal(t, expected, first)
+}
+
+func TestPerm(t *testing.T) {
+	perm := Perm(10, NewSeededRandom(5, 0))
+	require.Equal(t, perm, Perm(10, NewSeededRandom(5, 0)))
+	sort.Ints(perm)
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, perm)
+	require.Empty(t, Perm(0, NewRandom()))
+}
+
+func TestCryptoRandom(t *testing.T) {
+	rnd := NewCryptoRandom()
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		counts[rnd.Intn(3)]++
+	}
+	for _, count := range counts {
+		require.Greater(t, count, 800)
+	}
+	require.Equal(t, 0, rnd.Intn(1))
+	require.Panics(t, func() { rnd.Intn(0) })
+
+	codes := CreateDefaultCodes()
+	Shuffle(codes, rnd)
+	sort.Strings(codes)
+	expected := CreateDefaultCodes()
+	sort.Strings(expected)
+	require.Equal(t, expected, codes)
+}
+
+func TestIsValidRNG(t *testing.T) {
+	require.True(t, IsValidRNG(RNGMath))
+	require.True(t, IsValidRNG(RNGCrypto))
+	require.False(t, IsValidRNG(""))
+	require.False(t, IsValidRNG("dice"))
+}
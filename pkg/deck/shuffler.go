@@ -0,0 +1,135 @@
+package deckhelper
+
+import "sort"
+
+// Names of shuffle methods
+const (
+	// ShuffleUniform is Fisher–Yates shuffle, every order is equally likely
+	ShuffleUniform = "uniform"
+	// ShuffleRiffle is Gilbert–Shannon–Reeds model of riffle shuffle
+	ShuffleRiffle = "riffle"
+	// ShuffleOverhand is overhand shuffle moving small packets from the top to a new pile
+	ShuffleOverhand = "overhand"
+	// ShuffleStrip is strip shuffle moving large packets from the top to a new pile
+	ShuffleStrip = "strip"
+	// ShuffleCut is a single cut of deck at random position
+	ShuffleCut = "cut"
+)
+
+// Average sizes of packets moved by overhand and strip shuffles
+const (
+	overhandPacketSize = 5
+	stripPacketSize    = 10
+)
+
+// Shuffler shuffles codes in place using given random generator
+type Shuffler interface {
+	Shuffle(codes []string, rnd Random)
+}
+
+// ShufflerFunc is an adapter to use ordinary function as Shuffler
+type ShufflerFunc func(codes []string, rnd Random)
+
+// Shuffle calls f(codes, rnd)
+func (f ShufflerFunc) Shuffle(codes []string, rnd Random) {
+	f(codes, rnd)
+}
+
+var shufflers = map[string]Shuffler{
+	ShuffleUniform:  ShufflerFunc(Shuffle),
+	ShuffleRiffle:   ShufflerFunc(Riffle),
+	ShuffleOverhand: ShufflerFunc(Overhand),
+	ShuffleStrip:    ShufflerFunc(Strip),
+	ShuffleCut:      ShufflerFunc(Cut),
+}
+
+// LookupShuffler returns shuffler by method name, empty name means uniform shuffle
+func LookupShuffler(name string) (Shuffler, bool) {
+	if name == "" {
+		name = ShuffleUniform
+	}
+	shuffler, ok := shufflers[name]
+	return shuffler, ok
+}
+
+// ShufflerNames returns sorted names of all shuffle methods
+func ShufflerNames() []string {
+	names := make([]string, 0, len(shufflers))
+	for name := range shufflers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Repeat returns shuffler applying given one several times
+func Repeat(shuffler Shuffler, times uint) Shuffler {
+	return ShufflerFunc(func(codes []string, rnd Random) {
+		for i := uint(0); i < times; i++ {
+			shuffler.Shuffle(codes, rnd)
+		}
+	})
+}
+
+// Riffle shuffles codes by Gilbert–Shannon–Reeds model: deck is cut into two packets
+// by binomially distributed position, then cards are dropped from packets one by one,
+// each time from a packet with probability proportional to its size
+func Riffle(codes []string, rnd Random) {
+	n := len(codes)
+	cut := 0
+	for i := 0; i < n; i++ {
+		cut += rnd.Intn(2)
+	}
+
+	left := append([]string(nil), codes[:cut]...)
+	right := append([]string(nil), codes[cut:]...)
+	for i := range codes {
+		if rnd.Intn(len(left)+len(right)) < len(left) {
+			codes[i], left = left[0], left[1:]
+		} else {
+			codes[i], right = right[0], right[1:]
+		}
+	}
+}
+
+// Overhand shuffles codes by moving small packets from the top of deck onto a new pile,
+// so order of packets is reversed while order of cards inside packets is kept
+func Overhand(codes []string, rnd Random) {
+	movePackets(codes, rnd, overhandPacketSize)
+}
+
+// Strip shuffles codes like overhand shuffle but with larger packets
+func Strip(codes []string, rnd Random) {
+	movePackets(codes, rnd, stripPacketSize)
+}
+
+// Cut moves cards above random position to the bottom of deck, deck is never cut
+// at its top or bottom if it has at least two cards
+func Cut(codes []string, rnd Random) {
+	n := len(codes)
+	if n < 2 {
+		return
+	}
+	cut := 1 + rnd.Intn(n-1)
+	rotated := append(append([]string(nil), codes[cut:]...), codes[:cut]...)
+	copy(codes, rotated)
+}
+
+// movePackets splits codes into packets, each gap between cards is a packet boundary
+// with probability 1/packetSize, and puts packets in reversed order
+func movePackets(codes []string, rnd Random, packetSize int) {
+	n := len(codes)
+	if n < 2 {
+		return
+	}
+
+	result := make([]string, 0, n)
+	end := n
+	for start := n - 1; start >= 0; start-- {
+		if start == 0 || rnd.Intn(packetSize) == 0 {
+			result = append(result, codes[start:end]...)
+			end = start
+		}
+	}
+	copy(codes, result)
+}
@@ -0,0 +1,111 @@
+package deckhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// risingSequences counts maximal runs of consecutive original positions in shuffled order
+func risingSequences(codes, original []string) int {
+	positions := make(map[string]int, len(original))
+	for i, code := range original {
+		positions[code] = i
+	}
+	sequences := 1
+	for value := 1; value < len(original); value++ {
+		prev, cur := -1, -1
+		for i, code := range codes {
+			if positions[code] == value-1 {
+				prev = i
+			}
+			if positions[code] == value {
+				cur = i
+			}
+		}
+		if cur < prev {
+			sequences++
+		}
+	}
+	return sequences
+}
+
+func TestShufflers(t *testing.T) {
+	for _, name := range ShufflerNames() {
+		shuffler, ok := LookupShuffler(name)
+		require.True(t, ok)
+
+		codes := CreateDefaultCodes()
+		shuffler.Shuffle(codes, NewSeededRandom(1, 0))
+		require.ElementsMatch(t, CreateDefaultCodes(), codes, name)
+		require.NotEqual(t, CreateDefaultCodes(), codes, name)
+
+		same := CreateDefaultCodes()
+		shuffler.Shuffle(same, NewSeededRandom(1, 0))
+		require.Equal(t, codes, same, name)
+
+		for _, small := range [][]string{nil, {"AS"}} {
+			shuffler.Shuffle(small, NewRandom())
+		}
+	}
+
+	_, ok := LookupShuffler("")
+	require.True(t, ok)
+	_, ok = LookupShuffler("pile")
+	require.False(t, ok)
+}
+
+func TestRiffle(t *testing.T) {
+	for step := uint(0); step < 20; step++ {
+		codes := CreateDefaultCodes()
+		Riffle(codes, NewSeededRandom(3, step))
+		require.LessOrEqual(t, risingSequences(codes, CreateDefaultCodes()), 2)
+	}
+}
+
+func TestCut(t *testing.T) {
+	codes := []string{"AS", "2S", "3S", "4S"}
+	Cut(codes, NewSeededRandom(1, 0))
+	require.NotEqual(t, "AS", codes[0])
+	for i := range codes {
+		// cut keeps cyclic order
+		require.Equal(t, map[string]string{"AS": "2S", "2S": "3S", "3S": "4S", "4S": "AS"}[codes[i]], codes[(i+1)%len(codes)])
+	}
+}
+
+func TestOverhand(t *testing.T) {
+	original := CreateDefaultCodes()
+	codes := CreateDefaultCodes()
+	Overhand(codes, NewSeededRandom(1, 0))
+
+	// cards inside packets keep their order, packets are reversed
+	kept := 0
+	for i := 1; i < len(codes); i++ {
+		if indexOf(original, codes[i]) == indexOf(original, codes[i-1])+1 {
+			kept++
+		}
+	}
+	require.Greater(t, kept, len(codes)/2)
+	require.NotEqual(t, original[0], codes[0])
+}
+
+func TestRepeat(t *testing.T) {
+	once := CreateDefaultCodes()
+	Repeat(ShufflerFunc(Riffle), 1).Shuffle(once, NewSeededRandom(1, 0))
+	twice := CreateDefaultCodes()
+	Repeat(ShufflerFunc(Riffle), 2).Shuffle(twice, NewSeededRandom(1, 0))
+	require.NotEqual(t, once, twice)
+
+	none := CreateDefaultCodes()
+	Repeat(ShufflerFunc(Riffle), 0).Shuffle(none, NewSeededRandom(1, 0))
+	require.Equal(t, CreateDefaultCodes(), none)
+}
+
+func indexOf(codes []string, code string) int {
+	for i, c := range codes {
+		if c == code {
+			return i
+		}
+	}
+	return -1
+}
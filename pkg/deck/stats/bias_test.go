@@ -0,0 +1,81 @@
+//go:build stats
+// +build stats
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	biasDeckSize = 52
+	biasTrials   = 50000
+	// maxZScore is a bound of normalized chi-square, unbiased shuffle exceeds it with negligible probability
+	maxZScore = 5
+)
+
+// requireUnbiased checks that shuffler gives uniformly random order of deck
+func requireUnbiased(t *testing.T, shuffler deckhelper.Shuffler, rnd deckhelper.Random) {
+	report := Run(shuffler, rnd, biasDeckSize, biasTrials)
+	t.Logf("chi-square %.1f (df %d, z %.2f), rising sequences %.2f (expected %.2f), adjacent pairs %.3f (expected %.3f)",
+		report.ChiSquare, report.DegreesOfFreedom, report.ChiSquareZScore(),
+		report.MeanRisingSequences, report.ExpectedRisingSequences(),
+		report.MeanAdjacentPairs, report.ExpectedAdjacentPairs())
+
+	require.Less(t, report.ChiSquareZScore(), float64(maxZScore))
+	require.InDelta(t, report.ExpectedRisingSequences(), report.MeanRisingSequences, 0.1)
+	require.InDelta(t, report.ExpectedAdjacentPairs(), report.MeanAdjacentPairs, 0.05)
+}
+
+func TestUniformShuffleBias(t *testing.T) {
+	uniform := deckhelper.ShufflerFunc(deckhelper.Shuffle)
+
+	t.Run("math", func(t *testing.T) {
+		requireUnbiased(t, uniform, deckhelper.NewRandom())
+	})
+	t.Run("seeded", func(t *testing.T) {
+		requireUnbiased(t, uniform, deckhelper.NewSeededRandom(time.Now().UnixNano(), 0))
+	})
+	t.Run("crypto", func(t *testing.T) {
+		requireUnbiased(t, uniform, deckhelper.NewCryptoRandom())
+	})
+	t.Run("fair", func(t *testing.T) {
+		requireUnbiased(t, uniform, deckhelper.NewFairRandom(time.Now().String(), "client"))
+	})
+}
+
+func TestShuffleDeckBias(t *testing.T) {
+	requireUnbiased(t, deckhelper.ShufflerFunc(func(codes []string, _ deckhelper.Random) {
+		deckhelper.ShuffleDeck(codes)
+	}), nil)
+}
+
+// TestShufflers reports statistics of every registered shuffle method, so new methods are covered too.
+// Uniform method is checked to be unbiased, human shuffle simulations are biased by design,
+// e.g. few riffles keep rising sequences of original order
+func TestShufflers(t *testing.T) {
+	for _, name := range deckhelper.ShufflerNames() {
+		shuffler, ok := deckhelper.LookupShuffler(name)
+		require.True(t, ok)
+		if name == deckhelper.ShuffleUniform {
+			t.Run(name, func(t *testing.T) {
+				requireUnbiased(t, shuffler, deckhelper.NewRandom())
+			})
+			continue
+		}
+		for _, times := range []uint{1, 7} {
+			report := Run(deckhelper.Repeat(shuffler, times), deckhelper.NewRandom(), biasDeckSize, biasTrials/10)
+			t.Logf("%s x%d: chi-square z %.2f, rising sequences %.2f (expected %.2f), adjacent pairs %.3f (expected %.3f)",
+				name, times, report.ChiSquareZScore(),
+				report.MeanRisingSequences, report.ExpectedRisingSequences(),
+				report.MeanAdjacentPairs, report.ExpectedAdjacentPairs())
+		}
+	}
+
+	report := Run(deckhelper.ShufflerFunc(deckhelper.Riffle), deckhelper.NewRandom(), biasDeckSize, biasTrials/10)
+	require.LessOrEqual(t, report.MeanRisingSequences, 2.0)
+}
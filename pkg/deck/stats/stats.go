@@ -0,0 +1,145 @@
+// Package stats measures quality of shuffles by running shuffler many times
+// and comparing results with ones expected from uniformly random order
+package stats
+
+import (
+	"math"
+	"strconv"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+)
+
+// Report represents statistics of many shuffles of deck of the same size
+type Report struct {
+	// Size is a count of cards in shuffled deck
+	Size int
+	// Trials is a count of shuffles
+	Trials int
+	// Positions counts how many times card of original position (first index)
+	// ended up at position (second index)
+	Positions [][]int
+	// ChiSquare is Pearson's chi-square statistic of Positions against
+	// uniform distribution of each card over all positions
+	ChiSquare float64
+	// DegreesOfFreedom of ChiSquare statistic
+	DegreesOfFreedom int
+	// MeanRisingSequences is an average count of rising sequences in shuffled deck
+	MeanRisingSequences float64
+	// MeanAdjacentPairs is an average count of cards followed by the same card as in original order
+	MeanAdjacentPairs float64
+}
+
+// Run shuffles deck of given size given count of trials with given shuffler
+// and random generator and collects statistics of results
+func Run(shuffler deckhelper.Shuffler, rnd deckhelper.Random, size, trials int) *Report {
+	report := &Report{
+		Size:             size,
+		Trials:           trials,
+		Positions:        make([][]int, size),
+		DegreesOfFreedom: (size - 1) * (size - 1),
+	}
+	for i := range report.Positions {
+		report.Positions[i] = make([]int, size)
+	}
+
+	original := make([]string, size)
+	for i := range original {
+		original[i] = strconv.Itoa(i)
+	}
+
+	var rising, adjacent int
+	codes := make([]string, size)
+	perm := make([]int, size)
+	for trial := 0; trial < trials; trial++ {
+		copy(codes, original)
+		shuffler.Shuffle(codes, rnd)
+		for pos, code := range codes {
+			card, _ := strconv.Atoi(code)
+			perm[pos] = card
+			report.Positions[card][pos]++
+		}
+		rising += RisingSequences(perm)
+		adjacent += AdjacentPairs(perm)
+	}
+
+	if trials > 0 {
+		report.ChiSquare = PositionChiSquare(report.Positions, trials)
+		report.MeanRisingSequences = float64(rising) / float64(trials)
+		report.MeanAdjacentPairs = float64(adjacent) / float64(trials)
+	}
+
+	return report
+}
+
+// ExpectedRisingSequences returns average count of rising sequences of uniformly random order
+func (r *Report) ExpectedRisingSequences() float64 {
+	return float64(r.Size+1) / 2
+}
+
+// ExpectedAdjacentPairs returns average count of adjacent pairs of uniformly random order
+func (r *Report) ExpectedAdjacentPairs() float64 {
+	if r.Size == 0 {
+		return 0
+	}
+	return float64(r.Size-1) / float64(r.Size)
+}
+
+// ChiSquareZScore returns ChiSquare statistic normalized by Wilson–Hilferty approximation,
+// it is close to standard normal variable if shuffle is unbiased
+func (r *Report) ChiSquareZScore() float64 {
+	k := float64(r.DegreesOfFreedom)
+	if k == 0 {
+		return 0
+	}
+	return (math.Cbrt(r.ChiSquare/k) - (1 - 2/(9*k))) / math.Sqrt(2/(9*k))
+}
+
+// PositionChiSquare returns Pearson's chi-square statistic of counts of cards at positions
+// against uniform distribution, every card is expected trials/size times at each position
+func PositionChiSquare(positions [][]int, trials int) float64 {
+	size := len(positions)
+	if size == 0 || trials == 0 {
+		return 0
+	}
+
+	expected := float64(trials) / float64(size)
+	var chi float64
+	for _, counts := range positions {
+		for _, observed := range counts {
+			diff := float64(observed) - expected
+			chi += diff * diff / expected
+		}
+	}
+	return chi
+}
+
+// RisingSequences returns count of rising sequences of permutation, i.e. maximal sets of
+// consecutive original positions appearing in increasing order. One riffle shuffle gives at most 2
+func RisingSequences(perm []int) int {
+	if len(perm) == 0 {
+		return 0
+	}
+
+	at := make([]int, len(perm))
+	for pos, card := range perm {
+		at[card] = pos
+	}
+	sequences := 1
+	for card := 1; card < len(at); card++ {
+		if at[card] < at[card-1] {
+			sequences++
+		}
+	}
+	return sequences
+}
+
+// AdjacentPairs returns count of cards directly followed by the same card as in original order
+func AdjacentPairs(perm []int) int {
+	pairs := 0
+	for pos := 1; pos < len(perm); pos++ {
+		if perm[pos] == perm[pos-1]+1 {
+			pairs++
+		}
+	}
+	return pairs
+}
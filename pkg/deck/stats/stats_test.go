@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"testing"
+
+	deckhelper "github.com/card-deck/pkg/deck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRisingSequences(t *testing.T) {
+	require.Equal(t, 0, RisingSequences(nil))
+	require.Equal(t, 1, RisingSequences([]int{0, 1, 2, 3}))
+	require.Equal(t, 4, RisingSequences([]int{3, 2, 1, 0}))
+	// riffle of [0 1] and [2 3]
+	require.Equal(t, 2, RisingSequences([]int{0, 2, 1, 3}))
+}
+
+func TestAdjacentPairs(t *testing.T) {
+	require.Equal(t, 0, AdjacentPairs(nil))
+	require.Equal(t, 3, AdjacentPairs([]int{0, 1, 2, 3}))
+	require.Equal(t, 0, AdjacentPairs([]int{3, 2, 1, 0}))
+	require.Equal(t, 1, AdjacentPairs([]int{2, 3, 1, 0}))
+}
+
+func TestPositionChiSquare(t *testing.T) {
+	require.Equal(t, 0.0, PositionChiSquare([][]int{{5, 5}, {5, 5}}, 10))
+	require.Equal(t, 20.0, PositionChiSquare([][]int{{10, 0}, {0, 10}}, 10))
+	require.Equal(t, 0.0, PositionChiSquare(nil, 10))
+}
+
+func TestRun(t *testing.T) {
+	identity := deckhelper.ShufflerFunc(func([]string, deckhelper.Random) {})
+	report := Run(identity, deckhelper.NewSeededRandom(1, 0), 4, 10)
+	require.Equal(t, 1.0, report.MeanRisingSequences)
+	require.Equal(t, 3.0, report.MeanAdjacentPairs)
+	require.Equal(t, 9, report.DegreesOfFreedom)
+	require.Equal(t, 10, report.Positions[2][2])
+	require.Greater(t, report.ChiSquareZScore(), 4.0)
+
+	report = Run(deckhelper.ShufflerFunc(deckhelper.Shuffle), deckhelper.NewSeededRandom(1, 0), 4, 1000)
+	require.InDelta(t, report.ExpectedRisingSequences(), report.MeanRisingSequences, 0.2)
+	require.InDelta(t, report.ExpectedAdjacentPairs(), report.MeanAdjacentPairs, 0.2)
+}
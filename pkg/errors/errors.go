@@ -32,6 +32,12 @@ const (
 	// NotFound represents a not found error kind.
 	// It is used when trying to retrieve a nonexistent entity.
 	NotFound ErrorKind = http.StatusNotFound
+	// Conflict represents a conflict error kind.
+	// It is used when request conflicts with current state of entity.
+	Conflict ErrorKind = http.StatusConflict
+	// PreconditionFailed represents a precondition failed error kind.
+	// It is used when entity was changed since client has read it.
+	PreconditionFailed ErrorKind = http.StatusPreconditionFailed
 )
 
 // New creates a new instance of Error.
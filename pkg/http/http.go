@@ -1,9 +1,12 @@
 package httphelper
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -77,3 +80,65 @@ func ReadJSON(r *http.Request, receiver interface{}) error {
 	}
 	return nil
 }
+
+// ETag returns strong entity tag for given entity version
+func ETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// MatchETag checks if If-None-Match header value matches given entity tag
+// by weak comparison, weak tags are compared by their value
+func MatchETag(header, etag string) bool {
+	return matchETag(header, etag, false)
+}
+
+// MatchStrongETag checks if If-Match header value matches given entity tag
+// by strong comparison (RFC 7232 section 2.3.2), weak tags never match
+func MatchStrongETag(header, etag string) bool {
+	return matchETag(header, etag, true)
+}
+
+func matchETag(header, etag string, strong bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strong {
+			if !strings.HasPrefix(tag, "W/") && !strings.HasPrefix(etag, "W/") && tag == etag {
+				return true
+			}
+		} else if strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseRecorder is a type that writes response
+// to underlying writer and keeps copy of status and body
+type ResponseRecorder struct {
+	http.ResponseWriter
+	Status int
+	Body   bytes.Buffer
+}
+
+// NewResponseRecorder creates new instance of ResponseRecorder
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{
+		ResponseWriter: w,
+		Status:         http.StatusOK,
+	}
+}
+
+// WriteHeader implements the http.ResponseWriter interface.
+func (r *ResponseRecorder) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements the http.ResponseWriter interface.
+func (r *ResponseRecorder) Write(b []byte) (int, error) {
+	r.Body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
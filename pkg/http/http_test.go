@@ -2,6 +2,7 @@ package httphelper
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,3 +21,40 @@ func TestWriteSuccessResponse(t *testing.T) {
 	err := WriteSuccessResponse(rw, 401, "payload")
 	require.EqualError(t, err, "not valid status code 401 for success response")
 }
+
+func TestETag(t *testing.T) {
+	require.Equal(t, `"7"`, ETag(7))
+}
+
+func TestMatchETag(t *testing.T) {
+	require.True(t, MatchETag(`"7"`, `"7"`))
+	require.True(t, MatchETag(`W/"7"`, `"7"`))
+	require.True(t, MatchETag(`"5", "7"`, `"7"`))
+	require.True(t, MatchETag(`*`, `"7"`))
+	require.False(t, MatchETag(`"5"`, `"7"`))
+	require.False(t, MatchETag(``, `"7"`))
+}
+
+func TestMatchStrongETag(t *testing.T) {
+	require.True(t, MatchStrongETag(`"7"`, `"7"`))
+	require.True(t, MatchStrongETag(`"5", "7"`, `"7"`))
+	require.True(t, MatchStrongETag(`*`, `"7"`))
+	require.False(t, MatchStrongETag(`W/"7"`, `"7"`))
+	require.False(t, MatchStrongETag(`W/"7"`, `W/"7"`))
+	require.False(t, MatchStrongETag(`"5"`, `"7"`))
+	require.False(t, MatchStrongETag(``, `"7"`))
+}
+
+func TestResponseRecorder(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := NewResponseRecorder(w)
+
+	err := WriteSuccessResponse(rec, http.StatusCreated, map[string]string{"key": "value"})
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, rec.Status)
+	require.Equal(t, "{\"key\":\"value\"}\n", rec.Body.String())
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, rec.Body.String(), w.Body.String())
+	require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}